@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/verkestk/goetry/src/sound"
+)
+
+var soundPerson string
+var soundTop int
+var soundStrength float64
+
+var analyzeSoundCmd = &cobra.Command{
+	Use:   "analyze-sound",
+	Short: "prints each line of the corpus with its alliteration, assonance and consonance",
+	Args: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cor, _, err := loadCorpus(soundPerson)
+		if err != nil {
+			return fmt.Errorf("error loading corpus: %w", err)
+		}
+
+		rhymer, err := loadRhymer(cor, rhymerOptions())
+		if err != nil {
+			return fmt.Errorf("error loading rhymer: %w", err)
+		}
+
+		analyses := []*sound.Analysis{}
+		for _, line := range cor.Lines {
+			analyses = append(analyses, sound.Analyze(line, rhymer))
+		}
+
+		if soundTop > 0 {
+			sort.SliceStable(analyses, func(i, j int) bool {
+				return analyses[i].Score > analyses[j].Score
+			})
+			if len(analyses) > soundTop {
+				analyses = analyses[:soundTop]
+			}
+		}
+
+		for _, analysis := range analyses {
+			fmt.Println(analysis.Line)
+			for _, device := range analysis.Devices {
+				if device.Strength >= soundStrength {
+					fmt.Printf("  %s\n", device.Describe(analysis.Words))
+				}
+			}
+			fmt.Printf("  score %.2f\n\n", analysis.Score)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	analyzeSoundCmd.Flags().StringVarP(&corpusFilepath, "corpus", "c", "", "path to the corpus file, or a directory or glob pattern of corpus files")
+	addCorpusFlags(analyzeSoundCmd)
+	analyzeSoundCmd.Flags().StringVarP(&pronunciationDictionaryFilepath, "dictionary", "d", "", "path to the pronunciation dictionary file")
+	analyzeSoundCmd.Flags().StringArrayVarP(&extraDictionaryFilepaths, "extra-dictionary", "", nil, "path to an extra pronunciation dictionary file, overriding the pronunciation dictionary (can be repeated)")
+	analyzeSoundCmd.Flags().BoolVarP(&guessPronunciations, "guess-pronunciations", "", false, "guess the pronunciation of words missing from the dictionary from their spelling")
+	analyzeSoundCmd.Flags().StringVarP(&soundPerson, "person", "p", "", "only analyze lines by this person")
+	analyzeSoundCmd.Flags().IntVarP(&soundTop, "top", "n", 0, "only print this many lines, the most musical first (0 prints every line, in order)")
+	analyzeSoundCmd.Flags().Float64VarP(&soundStrength, "strength", "s", 0, "only print sound devices at least this strong")
+	analyzeSoundCmd.MarkFlagRequired("corpus")
+	analyzeSoundCmd.MarkFlagRequired("dictionary")
+	rootCmd.AddCommand(analyzeSoundCmd)
+}
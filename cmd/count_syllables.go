@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var syllablesPerson string
+
+var countSyllablesCmd = &cobra.Command{
+	Use:   "count-syllables",
+	Short: "annotates each line of the corpus with its possible syllable counts",
+	Args: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cor, _, err := loadCorpus(syllablesPerson)
+		if err != nil {
+			return fmt.Errorf("error loading corpus: %w", err)
+		}
+
+		rhymer, err := loadRhymer(cor, rhymerOptions())
+		if err != nil {
+			return fmt.Errorf("error loading rhymer: %w", err)
+		}
+
+		for _, line := range cor.Lines {
+			counts := rhymer.LineSyllables(line)
+			if len(counts) == 0 {
+				fmt.Printf("[?] %s\n", line)
+				continue
+			}
+
+			countStrs := []string{}
+			for _, count := range counts {
+				countStrs = append(countStrs, strconv.Itoa(count))
+			}
+			fmt.Printf("[%s] %s\n", strings.Join(countStrs, ", "), line)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	countSyllablesCmd.Flags().StringVarP(&corpusFilepath, "corpus", "c", "", "path to the corpus file, or a directory or glob pattern of corpus files")
+	addCorpusFlags(countSyllablesCmd)
+	countSyllablesCmd.Flags().StringVarP(&pronunciationDictionaryFilepath, "dictionary", "d", "", "path to the pronunciation dictionary file")
+	countSyllablesCmd.Flags().StringArrayVarP(&extraDictionaryFilepaths, "extra-dictionary", "", nil, "path to an extra pronunciation dictionary file, overriding the pronunciation dictionary (can be repeated)")
+	countSyllablesCmd.Flags().BoolVarP(&guessPronunciations, "guess-pronunciations", "", false, "guess the pronunciation of words missing from the dictionary from their spelling")
+	countSyllablesCmd.Flags().StringVarP(&syllablesPerson, "person", "p", "", "only count syllables for lines by this person")
+	countSyllablesCmd.MarkFlagRequired("corpus")
+	countSyllablesCmd.MarkFlagRequired("dictionary")
+	rootCmd.AddCommand(countSyllablesCmd)
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/verkestk/goetry/src/corpus"
+	"github.com/verkestk/goetry/src/form"
+	"github.com/verkestk/goetry/src/scheme"
+)
+
+var schemePoemFilepath string
+var schemeFormsDirectory string
+var schemeStrength int
+
+var detectRhymeSchemeCmd = &cobra.Command{
+	Use:   "detect-rhyme-scheme",
+	Short: "prints the rhyme scheme and meter of a poem or lyric, and guesses its form",
+	Args: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bytes, err := ioutil.ReadFile(schemePoemFilepath)
+		if err != nil {
+			return fmt.Errorf("error loading poem: %w", err)
+		}
+
+		stanzas := scheme.Parse(string(bytes))
+		if len(stanzas) == 0 {
+			return fmt.Errorf("poem has no lines")
+		}
+
+		cor := &corpus.Corpus{}
+		for _, stanza := range stanzas {
+			cor.Lines = append(cor.Lines, stanza...)
+		}
+
+		rhymer, err := loadRhymer(cor, rhymerOptions())
+		if err != nil {
+			return fmt.Errorf("error loading rhymer: %w", err)
+		}
+
+		analysis := scheme.Detect(stanzas, rhymer, schemeStrength)
+		for i, stanza := range analysis.Stanzas {
+			if i > 0 {
+				fmt.Println()
+			}
+			for _, line := range stanza {
+				if line.Scansion == nil {
+					fmt.Printf("%-4s %s\n       meter unknown\n", line.Rhyme, line.Text)
+				} else {
+					fmt.Printf("%-4s %s\n       %s, deviation %d\n", line.Rhyme, line.Text, line.Scansion, line.Scansion.Deviation)
+				}
+			}
+		}
+
+		fmt.Printf("\nrhyme scheme: %s\n", analysis.Scheme())
+
+		if schemeFormsDirectory != "" {
+			forms, err := form.LoadAll(schemeFormsDirectory)
+			if err != nil && !cmd.Flags().Changed("forms") {
+				// the default directory is relative, so it's only there when
+				// running from the repo
+				fmt.Fprintf(os.Stderr, "warning: not guessing the form: %s\n", err)
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			guessed, score := analysis.GuessForm(forms, rhymer)
+			if guessed == nil {
+				fmt.Println("form: unknown")
+			} else {
+				fmt.Printf("form: %s (score %.2f)\n", guessed.Name, score)
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	detectRhymeSchemeCmd.Flags().StringVarP(&schemePoemFilepath, "poem", "f", "", "path to a text file of the poem, one line per line and a blank line between stanzas")
+	detectRhymeSchemeCmd.Flags().StringVarP(&pronunciationDictionaryFilepath, "dictionary", "d", "", "path to the pronunciation dictionary file")
+	detectRhymeSchemeCmd.Flags().StringArrayVarP(&extraDictionaryFilepaths, "extra-dictionary", "", nil, "path to an extra pronunciation dictionary file, overriding the pronunciation dictionary (can be repeated)")
+	detectRhymeSchemeCmd.Flags().BoolVarP(&guessPronunciations, "guess-pronunciations", "", false, "guess the pronunciation of words missing from the dictionary from their spelling")
+	detectRhymeSchemeCmd.Flags().IntVarP(&schemeStrength, "strength", "s", 1, "the minimum rhyme strength for two lines to rhyme")
+	detectRhymeSchemeCmd.Flags().StringVarP(&schemeFormsDirectory, "forms", "", "forms", "directory of form definition files to guess the form from (empty to skip guessing)")
+	detectRhymeSchemeCmd.MarkFlagRequired("poem")
+	detectRhymeSchemeCmd.MarkFlagRequired("dictionary")
+	rootCmd.AddCommand(detectRhymeSchemeCmd)
+}
@@ -2,37 +2,58 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
-
-	"github.com/verkestk/goetry/src/corpus"
-	"github.com/verkestk/goetry/src/rhymes"
 )
 
 var findMissingPronunciationCmd = &cobra.Command{
 	Use:   "find-missing-pronunciation",
-	Short: "reports all words from corpus that have no pronunication in the dictionary",
+	Short: "reports all words from corpus that have no pronunication in the dictionary, with a guessed pronunciation",
 	Args: func(cmd *cobra.Command, args []string) error {
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cor, _, err := corpus.Load(corpusFilepath, "")
+		cor, _, err := loadCorpus("")
 		if err != nil {
 			return fmt.Errorf("error loading corpus: %w", err)
 		}
 
-		rhymer, err := rhymes.Load(pronunciationDictionaryFilepath, cor)
+		options := rhymerOptions()
+		options.GuessPronunciations = true
+		rhymer, err := loadRhymer(cor, options)
 		if err != nil {
 			return fmt.Errorf("error loading rhymer: %w", err)
 		}
 
+		resolved := rhymer.ResolvedPronunciations()
+		if len(resolved) > 0 {
+			fmt.Println("Pronunciation resolved by the extra dictionaries for the following words:")
+			for _, word := range resolved {
+				fmt.Printf("  %s  %s\n", word, strings.Join(rhymer.Pronunciations(word)[0], " "))
+			}
+		}
+
+		derived := rhymer.DerivedPronunciations()
+		if len(derived) > 0 {
+			fmt.Println("Pronunciation derived from the parts of the following words:")
+			for _, word := range derived {
+				fmt.Printf("  %s  %s\n", word, strings.Join(rhymer.Pronunciations(word)[0], " "))
+			}
+		}
+
 		missingPronunciation := rhymer.UnknownPronunciations()
 		if len(missingPronunciation) == 0 {
 			fmt.Println("There are no unknown pronunciations in the corpus.")
 		} else {
-			fmt.Println("Pronunciation missing for the following words:")
+			fmt.Println("Pronunciation missing for the following words (with a guess):")
 			for _, word := range missingPronunciation {
-				fmt.Printf("  %s\n", word)
+				guess := "(no guess)"
+				pronunciations := rhymer.Pronunciations(word)
+				if len(pronunciations) > 0 {
+					guess = strings.Join(pronunciations[0], " ")
+				}
+				fmt.Printf("  %s  %s\n", word, guess)
 			}
 		}
 
@@ -41,8 +62,10 @@ var findMissingPronunciationCmd = &cobra.Command{
 }
 
 func init() {
-	findMissingPronunciationCmd.Flags().StringVarP(&corpusFilepath, "corpus", "c", "", "path to the corpus file")
+	findMissingPronunciationCmd.Flags().StringVarP(&corpusFilepath, "corpus", "c", "", "path to the corpus file, or a directory or glob pattern of corpus files")
+	addCorpusFlags(findMissingPronunciationCmd)
 	findMissingPronunciationCmd.Flags().StringVarP(&pronunciationDictionaryFilepath, "dictionary", "d", "", "path to the pronunciation dictionary file")
+	findMissingPronunciationCmd.Flags().StringArrayVarP(&extraDictionaryFilepaths, "extra-dictionary", "", nil, "path to an extra pronunciation dictionary file, overriding the pronunciation dictionary (can be repeated)")
 	findMissingPronunciationCmd.MarkFlagRequired("corpus")
 	findMissingPronunciationCmd.MarkFlagRequired("dictionary")
 	rootCmd.AddCommand(findMissingPronunciationCmd)
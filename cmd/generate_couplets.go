@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/verkestk/goetry/src/poem"
+)
+
+var coupletsPerson string
+var coupletsCount int
+var coupletsStrength int
+var coupletsSyllables int
+var coupletsAttempts int
+
+var generateCoupletsCmd = &cobra.Command{
+	Use:   "generate-couplets",
+	Short: "generates rhyming couplets",
+	Args: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cor, _, err := loadCorpus(coupletsPerson)
+		if err != nil {
+			return fmt.Errorf("error loading corpus: %w", err)
+		}
+
+		rhymer, err := loadRhymer(cor, rhymerOptions())
+		if err != nil {
+			return fmt.Errorf("error loading rhymer: %w", err)
+		}
+
+		rand.Seed(time.Now().UnixNano())
+		generator := poem.NewGenerator(cor, rhymer, prefixLength, coupletsAttempts)
+		generator.AllowSlantRhymes(slant)
+
+		couplets, err := generator.Couplets(coupletsCount, coupletsStrength, coupletsSyllables)
+		if err != nil {
+			return fmt.Errorf("error generating couplets: %w", err)
+		}
+
+		fmt.Println(couplets)
+		return nil
+	},
+}
+
+func init() {
+	generateCoupletsCmd.Flags().StringVarP(&corpusFilepath, "corpus", "c", "", "path to the corpus file, or a directory or glob pattern of corpus files")
+	addCorpusFlags(generateCoupletsCmd)
+	generateCoupletsCmd.Flags().StringVarP(&pronunciationDictionaryFilepath, "dictionary", "d", "", "path to the pronunciation dictionary file")
+	generateCoupletsCmd.Flags().StringArrayVarP(&extraDictionaryFilepaths, "extra-dictionary", "", nil, "path to an extra pronunciation dictionary file, overriding the pronunciation dictionary (can be repeated)")
+	generateCoupletsCmd.Flags().BoolVarP(&guessPronunciations, "guess-pronunciations", "", false, "guess the pronunciation of words missing from the dictionary from their spelling")
+	generateCoupletsCmd.Flags().StringVarP(&coupletsPerson, "person", "p", "", "person to base the generated text from")
+	generateCoupletsCmd.Flags().IntVarP(&coupletsCount, "count", "n", 1, "number of couplets to generate")
+	generateCoupletsCmd.Flags().IntVarP(&coupletsStrength, "strength", "s", 1, "the minimum rhyme strength")
+	generateCoupletsCmd.Flags().IntVarP(&coupletsSyllables, "syllables", "", 10, "number of syllables in each line")
+	generateCoupletsCmd.Flags().IntVarP(&coupletsAttempts, "attempts", "a", 10000, "maximum number of words to draw from the markov chain for each line")
+	generateCoupletsCmd.Flags().IntVarP(&prefixLength, "prefix-length", "", 2, "length of markov chain prefix")
+	generateCoupletsCmd.Flags().Float64VarP(&slant, "slant", "", 0, "fall back on near rhymes scoring at least this (0 to 1) when there aren't enough perfect rhymes")
+	generateCoupletsCmd.MarkFlagRequired("corpus")
+	generateCoupletsCmd.MarkFlagRequired("dictionary")
+	rootCmd.AddCommand(generateCoupletsCmd)
+}
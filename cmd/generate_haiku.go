@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/verkestk/goetry/src/poem"
+)
+
+var haikuPerson string
+var haikuAttempts int
+
+var generateHaikuCmd = &cobra.Command{
+	Use:   "generate-haiku",
+	Short: "generates a haiku - three lines of 5, 7 and 5 syllables",
+	Args: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cor, _, err := loadCorpus(haikuPerson)
+		if err != nil {
+			return fmt.Errorf("error loading corpus: %w", err)
+		}
+
+		rhymer, err := loadRhymer(cor, rhymerOptions())
+		if err != nil {
+			return fmt.Errorf("error loading rhymer: %w", err)
+		}
+
+		rand.Seed(time.Now().UnixNano())
+		generator := poem.NewGenerator(cor, rhymer, prefixLength, haikuAttempts)
+
+		haiku, err := generator.Haiku()
+		if err != nil {
+			return fmt.Errorf("error generating haiku: %w", err)
+		}
+
+		fmt.Println(haiku)
+		return nil
+	},
+}
+
+func init() {
+	generateHaikuCmd.Flags().StringVarP(&corpusFilepath, "corpus", "c", "", "path to the corpus file, or a directory or glob pattern of corpus files")
+	addCorpusFlags(generateHaikuCmd)
+	generateHaikuCmd.Flags().StringVarP(&pronunciationDictionaryFilepath, "dictionary", "d", "", "path to the pronunciation dictionary file")
+	generateHaikuCmd.Flags().StringArrayVarP(&extraDictionaryFilepaths, "extra-dictionary", "", nil, "path to an extra pronunciation dictionary file, overriding the pronunciation dictionary (can be repeated)")
+	generateHaikuCmd.Flags().BoolVarP(&guessPronunciations, "guess-pronunciations", "", false, "guess the pronunciation of words missing from the dictionary from their spelling")
+	generateHaikuCmd.Flags().StringVarP(&haikuPerson, "person", "p", "", "person to base the generated text from")
+	generateHaikuCmd.Flags().IntVarP(&haikuAttempts, "attempts", "a", 10000, "maximum number of words to draw from the markov chain for each line")
+	generateHaikuCmd.Flags().IntVarP(&prefixLength, "prefix-length", "", 2, "length of markov chain prefix")
+	generateHaikuCmd.MarkFlagRequired("corpus")
+	generateHaikuCmd.MarkFlagRequired("dictionary")
+	rootCmd.AddCommand(generateHaikuCmd)
+}
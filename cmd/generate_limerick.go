@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/verkestk/goetry/src/poem"
+)
+
+var limerickPerson string
+var limerickStrength int
+var limerickTolerance int
+var limerickAttempts int
+var limerickScansion bool
+
+var generateLimerickCmd = &cobra.Command{
+	Use:   "generate-limerick",
+	Short: "generates a limerick - five lines of anapestic meter rhyming AABBA",
+	Args: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cor, _, err := loadCorpus(limerickPerson)
+		if err != nil {
+			return fmt.Errorf("error loading corpus: %w", err)
+		}
+
+		rhymer, err := loadRhymer(cor, rhymerOptions())
+		if err != nil {
+			return fmt.Errorf("error loading rhymer: %w", err)
+		}
+
+		rand.Seed(time.Now().UnixNano())
+		generator := poem.NewGenerator(cor, rhymer, prefixLength, limerickAttempts)
+		generator.AllowSlantRhymes(slant)
+
+		limerick, err := generator.Limerick(limerickStrength, limerickTolerance)
+		if err != nil {
+			return fmt.Errorf("error generating limerick: %w", err)
+		}
+
+		if limerickScansion {
+			fmt.Println(limerick.StringWithScansion())
+		} else {
+			fmt.Println(limerick)
+		}
+		return nil
+	},
+}
+
+func init() {
+	generateLimerickCmd.Flags().StringVarP(&corpusFilepath, "corpus", "c", "", "path to the corpus file, or a directory or glob pattern of corpus files")
+	addCorpusFlags(generateLimerickCmd)
+	generateLimerickCmd.Flags().StringVarP(&pronunciationDictionaryFilepath, "dictionary", "d", "", "path to the pronunciation dictionary file")
+	generateLimerickCmd.Flags().StringArrayVarP(&extraDictionaryFilepaths, "extra-dictionary", "", nil, "path to an extra pronunciation dictionary file, overriding the pronunciation dictionary (can be repeated)")
+	generateLimerickCmd.Flags().BoolVarP(&guessPronunciations, "guess-pronunciations", "", false, "guess the pronunciation of words missing from the dictionary from their spelling")
+	generateLimerickCmd.Flags().StringVarP(&limerickPerson, "person", "p", "", "person to base the generated text from")
+	generateLimerickCmd.Flags().IntVarP(&limerickStrength, "strength", "s", 1, "the minimum rhyme strength")
+	generateLimerickCmd.Flags().IntVarP(&limerickTolerance, "tolerance", "t", 1, "number of syllables in each line allowed to conflict with the meter")
+	generateLimerickCmd.Flags().BoolVarP(&limerickScansion, "scansion", "", false, "show the scansion under each line")
+	generateLimerickCmd.Flags().IntVarP(&limerickAttempts, "attempts", "a", 10000, "maximum number of words to draw from the markov chain for each line")
+	generateLimerickCmd.Flags().IntVarP(&prefixLength, "prefix-length", "", 2, "length of markov chain prefix")
+	generateLimerickCmd.Flags().Float64VarP(&slant, "slant", "", 0, "fall back on near rhymes scoring at least this (0 to 1) when there aren't enough perfect rhymes")
+	generateLimerickCmd.MarkFlagRequired("corpus")
+	generateLimerickCmd.MarkFlagRequired("dictionary")
+	rootCmd.AddCommand(generateLimerickCmd)
+}
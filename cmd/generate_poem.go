@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/verkestk/goetry/src/form"
+	"github.com/verkestk/goetry/src/poem"
+)
+
+var poemPerson string
+var poemFormFilepath string
+var poemAttempts int
+var poemScansion bool
+
+var generatePoemCmd = &cobra.Command{
+	Use:   "generate-poem",
+	Short: "generates a poem following a poetic form definition file",
+	Args: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := form.Load(poemFormFilepath)
+		if err != nil {
+			return fmt.Errorf("error loading form: %w", err)
+		}
+
+		cor, _, err := loadCorpus(poemPerson)
+		if err != nil {
+			return fmt.Errorf("error loading corpus: %w", err)
+		}
+
+		rhymer, err := loadRhymer(cor, rhymerOptions())
+		if err != nil {
+			return fmt.Errorf("error loading rhymer: %w", err)
+		}
+
+		rand.Seed(time.Now().UnixNano())
+		generator := poem.NewGenerator(cor, rhymer, prefixLength, poemAttempts)
+		generator.AllowSlantRhymes(slant)
+
+		p, err := generator.Form(f)
+		if err != nil {
+			return fmt.Errorf("error generating %s: %w", f.Name, err)
+		}
+
+		if poemScansion {
+			fmt.Println(p.StringWithScansion())
+		} else {
+			fmt.Println(p)
+		}
+		return nil
+	},
+}
+
+func init() {
+	generatePoemCmd.Flags().StringVarP(&corpusFilepath, "corpus", "c", "", "path to the corpus file, or a directory or glob pattern of corpus files")
+	addCorpusFlags(generatePoemCmd)
+	generatePoemCmd.Flags().StringVarP(&pronunciationDictionaryFilepath, "dictionary", "d", "", "path to the pronunciation dictionary file")
+	generatePoemCmd.Flags().StringArrayVarP(&extraDictionaryFilepaths, "extra-dictionary", "", nil, "path to an extra pronunciation dictionary file, overriding the pronunciation dictionary (can be repeated)")
+	generatePoemCmd.Flags().BoolVarP(&guessPronunciations, "guess-pronunciations", "", false, "guess the pronunciation of words missing from the dictionary from their spelling")
+	generatePoemCmd.Flags().StringVarP(&poemFormFilepath, "form", "f", "", "path to the poetic form definition file (YAML or JSON)")
+	generatePoemCmd.Flags().StringVarP(&poemPerson, "person", "p", "", "person to base the generated text from")
+	generatePoemCmd.Flags().BoolVarP(&poemScansion, "scansion", "", false, "show the scansion under each line with a meter")
+	generatePoemCmd.Flags().IntVarP(&poemAttempts, "attempts", "a", 10000, "maximum number of words to draw from the markov chain for each line")
+	generatePoemCmd.Flags().IntVarP(&prefixLength, "prefix-length", "", 2, "length of markov chain prefix")
+	generatePoemCmd.Flags().Float64VarP(&slant, "slant", "", 0, "fall back on near rhymes scoring at least this (0 to 1) when there aren't enough perfect rhymes")
+	generatePoemCmd.MarkFlagRequired("corpus")
+	generatePoemCmd.MarkFlagRequired("dictionary")
+	generatePoemCmd.MarkFlagRequired("form")
+	rootCmd.AddCommand(generatePoemCmd)
+}
@@ -10,7 +10,6 @@ import (
 
 	"github.com/verkestk/markovokram"
 
-	"github.com/verkestk/goetry/src/corpus"
 	"github.com/verkestk/goetry/src/util/markov"
 )
 
@@ -24,7 +23,7 @@ var generateSentencesCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cor, _, err := corpus.Load(corpusFilepath, sentencePerson)
+		cor, _, err := loadCorpus(sentencePerson)
 		if err != nil {
 			return fmt.Errorf("error loading corpus: %w", err)
 		}
@@ -41,7 +40,8 @@ var generateSentencesCmd = &cobra.Command{
 }
 
 func init() {
-	generateSentencesCmd.Flags().StringVarP(&corpusFilepath, "corpus", "c", "", "path to the corpus file")
+	generateSentencesCmd.Flags().StringVarP(&corpusFilepath, "corpus", "c", "", "path to the corpus file, or a directory or glob pattern of corpus files")
+	addCorpusFlags(generateSentencesCmd)
 	generateSentencesCmd.Flags().StringVarP(&sentencePerson, "person", "p", "", "person to base the generated text from")
 	generateSentencesCmd.Flags().IntVarP(&sentenceLength, "length", "l", 1, "number of sentences to generate")
 	generateSentencesCmd.Flags().IntVarP(&prefixLength, "prefix-length", "", 2, "length of markov chain prefix")
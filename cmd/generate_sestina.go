@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/verkestk/goetry/src/poem"
+)
+
+var sestinaPerson string
+var sestinaSyllables int
+var sestinaWords []string
+var sestinaAttempts int
+
+var generateSestinaCmd = &cobra.Command{
+	Use:   "generate-sestina",
+	Short: "generates a sestina - six stanzas rotating the same six end words, and an envoi",
+	Args: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cor, _, err := loadCorpus(sestinaPerson)
+		if err != nil {
+			return fmt.Errorf("error loading corpus: %w", err)
+		}
+
+		rhymer, err := loadRhymer(cor, rhymerOptions())
+		if err != nil {
+			return fmt.Errorf("error loading rhymer: %w", err)
+		}
+
+		rand.Seed(time.Now().UnixNano())
+		generator := poem.NewGenerator(cor, rhymer, prefixLength, sestinaAttempts)
+
+		sestina, err := generator.Sestina(sestinaSyllables, sestinaWords)
+		if err != nil {
+			return fmt.Errorf("error generating sestina: %w", err)
+		}
+
+		fmt.Println(sestina)
+		return nil
+	},
+}
+
+func init() {
+	generateSestinaCmd.Flags().StringVarP(&corpusFilepath, "corpus", "c", "", "path to the corpus file, or a directory or glob pattern of corpus files")
+	addCorpusFlags(generateSestinaCmd)
+	generateSestinaCmd.Flags().StringVarP(&pronunciationDictionaryFilepath, "dictionary", "d", "", "path to the pronunciation dictionary file")
+	generateSestinaCmd.Flags().StringArrayVarP(&extraDictionaryFilepaths, "extra-dictionary", "", nil, "path to an extra pronunciation dictionary file, overriding the pronunciation dictionary (can be repeated)")
+	generateSestinaCmd.Flags().BoolVarP(&guessPronunciations, "guess-pronunciations", "", false, "guess the pronunciation of words missing from the dictionary from their spelling")
+	generateSestinaCmd.Flags().StringVarP(&sestinaPerson, "person", "p", "", "person to base the generated text from")
+	generateSestinaCmd.Flags().IntVarP(&sestinaSyllables, "syllables", "", 10, "number of syllables in each line")
+	generateSestinaCmd.Flags().StringSliceVarP(&sestinaWords, "words", "w", nil, "the six end words, comma separated (if unspecified, uses the most common nouns in the corpus)")
+	generateSestinaCmd.Flags().IntVarP(&sestinaAttempts, "attempts", "a", 10000, "maximum number of words to draw from the markov chain for each line")
+	generateSestinaCmd.Flags().IntVarP(&prefixLength, "prefix-length", "", 2, "length of markov chain prefix")
+	generateSestinaCmd.MarkFlagRequired("corpus")
+	generateSestinaCmd.MarkFlagRequired("dictionary")
+	rootCmd.AddCommand(generateSestinaCmd)
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/verkestk/goetry/src/poem"
+)
+
+var sonnetPerson string
+var sonnetStrength int
+var sonnetTolerance int
+var sonnetAttempts int
+
+var generateSonnetCmd = &cobra.Command{
+	Use:   "generate-sonnet",
+	Short: "generates a Shakespearean sonnet - 14 lines of iambic pentameter rhyming ABAB CDCD EFEF GG",
+	Args: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cor, _, err := loadCorpus(sonnetPerson)
+		if err != nil {
+			return fmt.Errorf("error loading corpus: %w", err)
+		}
+
+		rhymer, err := loadRhymer(cor, rhymerOptions())
+		if err != nil {
+			return fmt.Errorf("error loading rhymer: %w", err)
+		}
+
+		rand.Seed(time.Now().UnixNano())
+		generator := poem.NewGenerator(cor, rhymer, prefixLength, sonnetAttempts)
+		generator.AllowSlantRhymes(slant)
+
+		sonnet, err := generator.Sonnet(sonnetStrength, sonnetTolerance)
+		if err != nil {
+			return fmt.Errorf("error generating sonnet: %w", err)
+		}
+
+		fmt.Println(sonnet)
+		return nil
+	},
+}
+
+func init() {
+	generateSonnetCmd.Flags().StringVarP(&corpusFilepath, "corpus", "c", "", "path to the corpus file, or a directory or glob pattern of corpus files")
+	addCorpusFlags(generateSonnetCmd)
+	generateSonnetCmd.Flags().StringVarP(&pronunciationDictionaryFilepath, "dictionary", "d", "", "path to the pronunciation dictionary file")
+	generateSonnetCmd.Flags().StringArrayVarP(&extraDictionaryFilepaths, "extra-dictionary", "", nil, "path to an extra pronunciation dictionary file, overriding the pronunciation dictionary (can be repeated)")
+	generateSonnetCmd.Flags().BoolVarP(&guessPronunciations, "guess-pronunciations", "", false, "guess the pronunciation of words missing from the dictionary from their spelling")
+	generateSonnetCmd.Flags().StringVarP(&sonnetPerson, "person", "p", "", "person to base the generated text from")
+	generateSonnetCmd.Flags().IntVarP(&sonnetStrength, "strength", "s", 1, "the minimum rhyme strength")
+	generateSonnetCmd.Flags().IntVarP(&sonnetTolerance, "tolerance", "t", 2, "number of syllables in each line allowed to conflict with the meter")
+	generateSonnetCmd.Flags().IntVarP(&sonnetAttempts, "attempts", "a", 10000, "maximum number of words to draw from the markov chain for each line")
+	generateSonnetCmd.Flags().IntVarP(&prefixLength, "prefix-length", "", 2, "length of markov chain prefix")
+	generateSonnetCmd.Flags().Float64VarP(&slant, "slant", "", 0, "fall back on near rhymes scoring at least this (0 to 1) when there aren't enough perfect rhymes")
+	generateSonnetCmd.MarkFlagRequired("corpus")
+	generateSonnetCmd.MarkFlagRequired("dictionary")
+	rootCmd.AddCommand(generateSonnetCmd)
+}
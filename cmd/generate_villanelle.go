@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/verkestk/goetry/src/poem"
+)
+
+var villanellePerson string
+var villanelleStrength int
+var villanelleTolerance int
+var villanelleAttempts int
+var villanelleScansion bool
+
+var generateVillanelleCmd = &cobra.Command{
+	Use:   "generate-villanelle",
+	Short: "generates a villanelle - nineteen lines of iambic pentameter with two refrains and two rhymes",
+	Args: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cor, _, err := loadCorpus(villanellePerson)
+		if err != nil {
+			return fmt.Errorf("error loading corpus: %w", err)
+		}
+
+		rhymer, err := loadRhymer(cor, rhymerOptions())
+		if err != nil {
+			return fmt.Errorf("error loading rhymer: %w", err)
+		}
+
+		rand.Seed(time.Now().UnixNano())
+		generator := poem.NewGenerator(cor, rhymer, prefixLength, villanelleAttempts)
+		generator.AllowSlantRhymes(slant)
+
+		villanelle, err := generator.Villanelle(villanelleStrength, villanelleTolerance)
+		if err != nil {
+			return fmt.Errorf("error generating villanelle: %w", err)
+		}
+
+		if villanelleScansion {
+			fmt.Println(villanelle.StringWithScansion())
+		} else {
+			fmt.Println(villanelle)
+		}
+		return nil
+	},
+}
+
+func init() {
+	generateVillanelleCmd.Flags().StringVarP(&corpusFilepath, "corpus", "c", "", "path to the corpus file, or a directory or glob pattern of corpus files")
+	addCorpusFlags(generateVillanelleCmd)
+	generateVillanelleCmd.Flags().StringVarP(&pronunciationDictionaryFilepath, "dictionary", "d", "", "path to the pronunciation dictionary file")
+	generateVillanelleCmd.Flags().StringArrayVarP(&extraDictionaryFilepaths, "extra-dictionary", "", nil, "path to an extra pronunciation dictionary file, overriding the pronunciation dictionary (can be repeated)")
+	generateVillanelleCmd.Flags().BoolVarP(&guessPronunciations, "guess-pronunciations", "", false, "guess the pronunciation of words missing from the dictionary from their spelling")
+	generateVillanelleCmd.Flags().StringVarP(&villanellePerson, "person", "p", "", "person to base the generated text from")
+	generateVillanelleCmd.Flags().IntVarP(&villanelleStrength, "strength", "s", 1, "the minimum rhyme strength")
+	generateVillanelleCmd.Flags().IntVarP(&villanelleTolerance, "tolerance", "t", 2, "number of syllables in each line allowed to conflict with the meter")
+	generateVillanelleCmd.Flags().BoolVarP(&villanelleScansion, "scansion", "", false, "show the scansion under each line")
+	generateVillanelleCmd.Flags().IntVarP(&villanelleAttempts, "attempts", "a", 10000, "maximum number of words to draw from the markov chain for each line")
+	generateVillanelleCmd.Flags().IntVarP(&prefixLength, "prefix-length", "", 2, "length of markov chain prefix")
+	generateVillanelleCmd.Flags().Float64VarP(&slant, "slant", "", 0, "fall back on near rhymes scoring at least this (0 to 1) when there aren't enough perfect rhymes")
+	generateVillanelleCmd.MarkFlagRequired("corpus")
+	generateVillanelleCmd.MarkFlagRequired("dictionary")
+	rootCmd.AddCommand(generateVillanelleCmd)
+}
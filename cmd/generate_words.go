@@ -10,7 +10,6 @@ import (
 
 	"github.com/verkestk/markovokram"
 
-	"github.com/verkestk/goetry/src/corpus"
 	"github.com/verkestk/goetry/src/util/markov"
 )
 
@@ -24,7 +23,7 @@ var generateWordsCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cor, _, err := corpus.Load(corpusFilepath, wordPerson)
+		cor, _, err := loadCorpus(wordPerson)
 		if err != nil {
 			return fmt.Errorf("error loading corpus: %w", err)
 		}
@@ -41,7 +40,8 @@ var generateWordsCmd = &cobra.Command{
 }
 
 func init() {
-	generateWordsCmd.Flags().StringVarP(&corpusFilepath, "corpus", "c", "", "path to the corpus file")
+	generateWordsCmd.Flags().StringVarP(&corpusFilepath, "corpus", "c", "", "path to the corpus file, or a directory or glob pattern of corpus files")
+	addCorpusFlags(generateWordsCmd)
 	generateWordsCmd.Flags().StringVarP(&wordPerson, "person", "p", "", "person to base the generated text from")
 	generateWordsCmd.Flags().IntVarP(&wordLength, "length", "l", 10, "number of words to generate")
 	generateWordsCmd.Flags().IntVarP(&prefixLength, "prefix-length", "", 2, "length of markov chain prefix")
@@ -6,46 +6,75 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/verkestk/goetry/src/corpus"
 	"github.com/verkestk/goetry/src/rhymes"
 )
 
+var rhymesPerson string
 var rhymesWord string
 var rhymesStrength int
 var rhymesMax int
+var rhymesPhrases bool
+var rhymesFullDictionary bool
 
 var getRhymesCmd = &cobra.Command{
 	Use:   "get-rhymes",
-	Short: "gets rhymes from a corpus for a word, ordered by descending strength",
+	Short: "gets rhymes from a corpus for a word or phrase, ordered by descending strength (or score, for near rhymes)",
 	Args: func(cmd *cobra.Command, args []string) error {
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cor, _, err := corpus.Load(corpusFilepath, "")
+		// a phrase of several words rhymes with phrases as well as words
+		phrases := rhymesPhrases || len(rhymes.Tokenize(rhymesWord)) > 1
+		if rhymesFullDictionary && (phrases || slant > 0) {
+			return fmt.Errorf("--full-dictionary can't be combined with --slant or phrases")
+		}
+		if phrases && slant > 0 {
+			return fmt.Errorf("--slant can't be combined with phrases")
+		}
+
+		cor, _, err := loadCorpus(rhymesPerson)
 		if err != nil {
 			return fmt.Errorf("error loading corpus: %w", err)
 		}
 
-		rhymer, err := rhymes.Load(pronunciationDictionaryFilepath, cor)
+		rhymer, err := loadRhymer(cor, rhymerOptions())
 		if err != nil {
 			return fmt.Errorf("error loading rhymer: %w", err)
 		}
 
-		pronunciations := rhymer.Pronunciations(rhymesWord)
+		pronunciations := rhymer.PhrasePronunciations(rhymesWord)
 		if len(pronunciations) == 0 {
 			return fmt.Errorf("rhyming dictionary missing pronuncation for %s", rhymesWord)
 		}
 
 		for _, pronunciation := range pronunciations {
 
-			rhymes := rhymer.Rhymes(rhymesWord, pronunciation, rhymesStrength)
-			if len(rhymes) > rhymesMax {
-				rhymes = rhymes[:rhymesMax]
+			var found []*rhymes.Rhyme
+			if slant > 0 {
+				found = rhymer.SlantRhymes(rhymesWord, pronunciation, slant)
+			} else if phrases {
+				found = rhymer.MosaicRhymes(rhymesWord, pronunciation, rhymesStrength)
+			} else if rhymesFullDictionary {
+				found = rhymer.DictionaryRhymes(rhymesWord, pronunciation, rhymesStrength)
+			} else {
+				found = rhymer.Rhymes(rhymesWord, pronunciation, rhymesStrength)
+			}
+			if len(found) > rhymesMax {
+				found = found[:rhymesMax]
 			}
 
 			fmt.Printf("\nrhymes for %s (%s):\n", rhymesWord, strings.Join(pronunciation, " "))
-			for _, rhyme := range rhymes {
-				fmt.Printf("  %s (%s)\n", rhyme.Word, strings.Join(rhyme.Pronunciation, " "))
+			for _, rhyme := range found {
+				details := []string{strings.Join(rhyme.Pronunciation, " ")}
+				if slant > 0 {
+					details = append(details, fmt.Sprintf("%s %.2f", rhyme.Kind, rhyme.Score))
+				}
+				if rhyme.Guessed {
+					details = append(details, "guessed")
+				} else if rhyme.Derived {
+					details = append(details, "derived")
+				}
+				fmt.Printf("  %s (%s)\n", rhyme.Word, strings.Join(details, ", "))
 			}
 		}
 
@@ -54,10 +83,17 @@ var getRhymesCmd = &cobra.Command{
 }
 
 func init() {
-	getRhymesCmd.Flags().StringVarP(&corpusFilepath, "corpus", "c", "", "path to the corpus file")
+	getRhymesCmd.Flags().StringVarP(&corpusFilepath, "corpus", "c", "", "path to the corpus file, or a directory or glob pattern of corpus files")
+	addCorpusFlags(getRhymesCmd)
 	getRhymesCmd.Flags().StringVarP(&pronunciationDictionaryFilepath, "dictionary", "d", "", "path to the pronunciation dictionary file")
-	getRhymesCmd.Flags().StringVarP(&rhymesWord, "word", "w", "", "the word for which to find rhymes")
+	getRhymesCmd.Flags().StringArrayVarP(&extraDictionaryFilepaths, "extra-dictionary", "", nil, "path to an extra pronunciation dictionary file, overriding the pronunciation dictionary (can be repeated)")
+	getRhymesCmd.Flags().BoolVarP(&guessPronunciations, "guess-pronunciations", "", false, "guess the pronunciation of words missing from the dictionary from their spelling")
+	getRhymesCmd.Flags().StringVarP(&rhymesPerson, "person", "p", "", "only get rhymes from lines by this person")
+	getRhymesCmd.Flags().StringVarP(&rhymesWord, "word", "w", "", "the word or phrase for which to find rhymes")
+	getRhymesCmd.Flags().BoolVarP(&rhymesPhrases, "phrases", "", false, "also get phrases ending corpus lines and clauses that rhyme with the word (always on for a phrase)")
 	getRhymesCmd.Flags().IntVarP(&rhymesStrength, "strength", "s", 1, "the minimum rhyme strength")
+	getRhymesCmd.Flags().Float64VarP(&slant, "slant", "", 0, "get near rhymes - assonant, consonant and slant rhymes - scoring at least this (0 to 1), ordered by descending score, instead of rhymes by strength (not for phrases)")
+	getRhymesCmd.Flags().BoolVarP(&rhymesFullDictionary, "full-dictionary", "", false, "get rhymes from every word of the pronunciation dictionary, not just the corpus")
 	getRhymesCmd.Flags().IntVarP(&rhymesMax, "max", "m", 20, "the minimum rhyme strength")
 	getRhymesCmd.MarkFlagRequired("corpus")
 	getRhymesCmd.MarkFlagRequired("dictionary")
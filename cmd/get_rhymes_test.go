@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_getRhymes(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-w", "poet"}, ""},
+		{[]string{"-w", "know it"}, ""},
+		{[]string{"-w", "know it", "--slant", "0.5"}, "--slant can't be combined with phrases"},
+		{[]string{"-w", "poet", "--phrases", "--slant", "0.5"}, "--slant can't be combined with phrases"},
+		{[]string{"-w", "poet", "--slant", "0.5"}, ""},
+		{[]string{"-w", "know it", "--full-dictionary"}, "--full-dictionary can't be combined with --slant or phrases"},
+	}
+
+	for _, test := range tests {
+		slant = 0
+		rhymesPhrases = false
+		rhymesFullDictionary = false
+
+		args := append([]string{"get-rhymes", "-c", "test_mosaic_corpus.txt", "-d", "../src/rhymes/test_mosaic_dictionary.txt"}, test.args...)
+		rootCmd.SetArgs(args)
+		_, err := rootCmd.ExecuteC()
+
+		if test.expected == "" && err != nil {
+			t.Errorf("expected no error for %s, got %v", strings.Join(test.args, " "), err)
+		}
+		if test.expected != "" && (err == nil || err.Error() != test.expected) {
+			t.Errorf("expected error \"%s\" for %s, got %v", test.expected, strings.Join(test.args, " "), err)
+		}
+	}
+}
@@ -4,8 +4,6 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
-
-	"github.com/verkestk/goetry/src/corpus"
 )
 
 var listPeopleCmd = &cobra.Command{
@@ -15,7 +13,7 @@ var listPeopleCmd = &cobra.Command{
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		_, people, err := corpus.Load(corpusFilepath, "")
+		_, people, err := loadCorpus("")
 		if err != nil {
 			return fmt.Errorf("error loading corpus: %w", err)
 		}
@@ -29,7 +27,8 @@ var listPeopleCmd = &cobra.Command{
 }
 
 func init() {
-	listPeopleCmd.Flags().StringVarP(&corpusFilepath, "corpus", "c", "", "path to the corpus file")
+	listPeopleCmd.Flags().StringVarP(&corpusFilepath, "corpus", "c", "", "path to the corpus file, or a directory or glob pattern of corpus files")
+	addCorpusFlags(listPeopleCmd)
 	listPeopleCmd.MarkFlagRequired("corpus")
 	rootCmd.AddCommand(listPeopleCmd)
 }
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/verkestk/goetry/src/rhymes"
+)
+
+var familiesPerson string
+var familiesStrength int
+var familiesMinSize int
+var familiesMax int
+var familiesSort string
+var familiesJSON bool
+
+var rhymeFamiliesCmd = &cobra.Command{
+	Use:   "rhyme-families",
+	Short: "groups the words of a corpus into rhyme families, the largest first",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if familiesSort != "size" && familiesSort != "frequency" {
+			return fmt.Errorf("--sort must be size or frequency, got %s", familiesSort)
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cor, _, err := loadCorpus(familiesPerson)
+		if err != nil {
+			return fmt.Errorf("error loading corpus: %w", err)
+		}
+
+		rhymer, err := loadRhymer(cor, rhymerOptions())
+		if err != nil {
+			return fmt.Errorf("error loading rhymer: %w", err)
+		}
+
+		families := []*rhymes.RhymeFamily{}
+		for _, family := range rhymer.RhymeFamilies(familiesStrength) {
+			if len(family.Words) >= familiesMinSize {
+				families = append(families, family)
+			}
+		}
+
+		if familiesSort == "frequency" {
+			sort.SliceStable(families, func(i, j int) bool {
+				return families[i].Frequency > families[j].Frequency
+			})
+		}
+		if familiesMax > 0 && len(families) > familiesMax {
+			families = families[:familiesMax]
+		}
+
+		if familiesJSON {
+			bytes, err := json.MarshalIndent(families, "", "  ")
+			if err != nil {
+				return fmt.Errorf("error writing rhyme families: %w", err)
+			}
+			fmt.Println(string(bytes))
+			return nil
+		}
+
+		for _, family := range families {
+			fmt.Printf("%s (%d words, %d uses): %s\n", family.Sound, len(family.Words), family.Frequency, strings.Join(family.Words, ", "))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rhymeFamiliesCmd.Flags().StringVarP(&corpusFilepath, "corpus", "c", "", "path to the corpus file, or a directory or glob pattern of corpus files")
+	addCorpusFlags(rhymeFamiliesCmd)
+	rhymeFamiliesCmd.Flags().StringVarP(&pronunciationDictionaryFilepath, "dictionary", "d", "", "path to the pronunciation dictionary file")
+	rhymeFamiliesCmd.Flags().StringArrayVarP(&extraDictionaryFilepaths, "extra-dictionary", "", nil, "path to an extra pronunciation dictionary file, overriding the pronunciation dictionary (can be repeated)")
+	rhymeFamiliesCmd.Flags().BoolVarP(&guessPronunciations, "guess-pronunciations", "", false, "guess the pronunciation of words missing from the dictionary from their spelling")
+	rhymeFamiliesCmd.Flags().StringVarP(&familiesPerson, "person", "p", "", "only group words from lines by this person")
+	rhymeFamiliesCmd.Flags().IntVarP(&familiesStrength, "strength", "s", 1, "the minimum rhyme strength of the words in a family")
+	rhymeFamiliesCmd.Flags().IntVarP(&familiesMinSize, "min-size", "", 2, "the fewest words in a family to list it")
+	rhymeFamiliesCmd.Flags().IntVarP(&familiesMax, "max", "m", 20, "the number of families to list (0 for all)")
+	rhymeFamiliesCmd.Flags().StringVarP(&familiesSort, "sort", "", "size", "list the families by size (number of words) or frequency (uses of the words in the corpus)")
+	rhymeFamiliesCmd.Flags().BoolVarP(&familiesJSON, "json", "", false, "print the families as JSON")
+	rhymeFamiliesCmd.MarkFlagRequired("corpus")
+	rhymeFamiliesCmd.MarkFlagRequired("dictionary")
+	rootCmd.AddCommand(rhymeFamiliesCmd)
+}
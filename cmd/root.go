@@ -3,8 +3,12 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/verkestk/goetry/src/corpus"
+	"github.com/verkestk/goetry/src/rhymes"
 )
 
 var rootCmd = &cobra.Command{
@@ -18,7 +22,65 @@ var rootCmd = &cobra.Command{
 
 var corpusFilepath string
 var pronunciationDictionaryFilepath string
+var guessPronunciations bool
+var extraDictionaryFilepaths []string
 var prefixLength int
+var slant float64
+var corpusFormat string
+var corpusLineColumn string
+var corpusPersonColumn string
+var stageDirections bool
+var corpusScenes bool
+var where string
+
+// addCorpusFlags adds the flags for reading and filtering the corpus, to a
+// command with a --corpus flag
+func addCorpusFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&corpusFormat, "corpus-format", "", fmt.Sprintf("format of the corpus file (%s) - chosen by the file extension by default", strings.Join(corpus.Formats(), ", ")))
+	cmd.Flags().StringVar(&corpusLineColumn, "line-column", "", "name of the column holding the lines, for csv and tsv corpus files (default \"line\")")
+	cmd.Flags().StringVar(&corpusPersonColumn, "person-column", "", "name of the column holding the people, for csv and tsv corpus files (default \"person\")")
+	cmd.Flags().BoolVar(&stageDirections, "stage-directions", false, "keep the stage directions of a screenplay corpus file, as lines without a person")
+	cmd.Flags().BoolVar(&corpusScenes, "scenes", false, "give each line of a screenplay corpus file the heading of its scene, for --where")
+	cmd.Flags().StringVar(&where, "where", "", "only use the lines of the corpus matching a filter expression, e.g. 'season >= 3 && person in (\"picard\", \"riker\")'")
+}
+
+// loadCorpus loads the corpus file, in the format chosen by the flags shared by
+// the commands that use one
+func loadCorpus(person string) (*corpus.Corpus, []string, error) {
+	return corpus.LoadWithOptions(corpusFilepath, corpus.Options{
+		Person:          person,
+		Format:          corpusFormat,
+		LineColumn:      corpusLineColumn,
+		PersonColumn:    corpusPersonColumn,
+		StageDirections: stageDirections,
+		Scenes:          corpusScenes,
+		Where:           where,
+	})
+}
+
+// rhymerOptions are the options for loading a Rhymer, from the flags shared by
+// the commands that use one
+func rhymerOptions() rhymes.Options {
+	return rhymes.Options{
+		GuessPronunciations: guessPronunciations,
+		ExtraDictionaries:   extraDictionaryFilepaths,
+	}
+}
+
+// loadRhymer loads the pronunciation dictionary for a corpus, printing any
+// warnings from reading the dictionary
+func loadRhymer(cor *corpus.Corpus, options rhymes.Options) (*rhymes.Rhymer, error) {
+	rhymer, err := rhymes.LoadWithOptions(pronunciationDictionaryFilepath, cor, options)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, warning := range rhymer.Warnings() {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+	}
+
+	return rhymer, nil
+}
 
 // Execute executes a CLI command - boilerplate for cobra
 func Execute() {
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/verkestk/goetry/src/meter"
+	"github.com/verkestk/goetry/src/rhymes"
+)
+
+var scanPerson string
+
+var scanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "prints each line of the corpus with the stress of each word and the best matching metrical foot",
+	Args: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cor, _, err := loadCorpus(scanPerson)
+		if err != nil {
+			return fmt.Errorf("error loading corpus: %w", err)
+		}
+
+		rhymer, err := loadRhymer(cor, rhymerOptions())
+		if err != nil {
+			return fmt.Errorf("error loading rhymer: %w", err)
+		}
+
+		for _, line := range cor.Lines {
+			fmt.Println(line)
+
+			words := rhymes.Tokenize(line)
+			wordStresses := rhymer.LineStresses(line)
+			if wordStresses == nil {
+				marks := []string{}
+				for _, word := range words {
+					stresses := rhymer.Stresses(word)
+					if len(stresses) == 0 {
+						marks = append(marks, fmt.Sprintf("%s(?)", strings.ToLower(word)))
+					} else {
+						marks = append(marks, fmt.Sprintf("%s(%s)", strings.ToLower(word), strings.Join(stresses, "|")))
+					}
+				}
+				fmt.Printf("  %s\n", strings.Join(marks, " "))
+				fmt.Printf("  meter unknown\n\n")
+				continue
+			}
+
+			scansion := meter.BestFoot(wordStresses)
+			marks := []string{}
+			for i, word := range words {
+				marks = append(marks, fmt.Sprintf("%s(%s)", strings.ToLower(word), scansion.Stresses[i]))
+			}
+			fmt.Printf("  %s\n", strings.Join(marks, " "))
+			fmt.Printf("  %s: %s, deviation %d\n\n", scansion.Foot.Name, scansion, scansion.Deviation)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	scanCmd.Flags().StringVarP(&corpusFilepath, "corpus", "c", "", "path to the corpus file, or a directory or glob pattern of corpus files")
+	addCorpusFlags(scanCmd)
+	scanCmd.Flags().StringVarP(&pronunciationDictionaryFilepath, "dictionary", "d", "", "path to the pronunciation dictionary file")
+	scanCmd.Flags().StringArrayVarP(&extraDictionaryFilepaths, "extra-dictionary", "", nil, "path to an extra pronunciation dictionary file, overriding the pronunciation dictionary (can be repeated)")
+	scanCmd.Flags().BoolVarP(&guessPronunciations, "guess-pronunciations", "", false, "guess the pronunciation of words missing from the dictionary from their spelling")
+	scanCmd.Flags().StringVarP(&scanPerson, "person", "p", "", "only scan lines by this person")
+	scanCmd.MarkFlagRequired("corpus")
+	scanCmd.MarkFlagRequired("dictionary")
+	rootCmd.AddCommand(scanCmd)
+}
@@ -5,4 +5,5 @@ go 1.16
 require (
 	github.com/spf13/cobra v1.1.3
 	github.com/verkestk/markovokram v0.1.0
+	gopkg.in/yaml.v2 v2.4.0
 )
@@ -1,55 +1,139 @@
 package corpus
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Corpus is simply a collection of strings
 type Corpus struct {
 	Lines []string
+
+	// the lines with the people who said them and where they're from, in the
+	// same order as Lines
+	Attributed []*Line
+}
+
+// Line is a line of a corpus file, and the person who said it
+type Line struct {
+	Line   string
+	Person string
+
+	// the heading of the scene of a screenplay the line is from, if known
+	Scene string
+
+	// when the line is said, for lines from subtitles
+	Start time.Duration
+	End   time.Duration
+
+	// the path of the corpus file the line is from
+	Source string
+
+	// any other fields of the line, like "season" or "date", by lowercase name
+	Metadata map[string]string
 }
 
+// Options changes how a corpus is loaded
+type Options struct {
+	// only load the lines by this person, or every line if empty
+	Person string
+
+	// the name of the format of the corpus file (see Formats), or empty to
+	// choose the format by the file extension
+	Format string
+
+	// the names of the columns holding the lines and the people, for the
+	// formats with columns like CSV - "line" and "person" if empty
+	LineColumn   string
+	PersonColumn string
+
+	// for screenplays, whether to keep the stage directions as lines without a
+	// person, and whether to give each line the heading of its scene
+	StageDirections bool
+	Scenes          bool
+
+	// only load the lines passing this filter expression (see Filter), or
+	// every line if empty
+	Where string
+}
+
+// the season and episode in the name of a corpus file, like "s07e12"
+var episodeName = regexp.MustCompile(`(?i)(?:^|[^a-z])s(\d+)\s*e(\d+)`)
+
 // Load builds a corpus from a json file. This file attributes lines to specific
 // "people". The corpus can be filtered to only lines by a specific "person". If
 // person is an empty string, the corpus will not be filtered.
 func Load(corpusFilepath string, person string) (*Corpus, []string, error) {
+	return LoadWithOptions(corpusFilepath, Options{Person: person})
+}
 
-	type line struct {
-		Line   string
-		Person string
+// LoadWithOptions builds a corpus like Load, from a file in any of the
+// registered formats (see RegisterFormat). The path can also be a directory, to
+// load every file in it (and its subdirectories) with the extension of a format,
+// or a glob pattern like "scripts/s01e*.txt". Lines are read one at a time, and
+// each remembers the file it's from. Returns the corpus, and every person in the
+// files - or with lines passing the filter, if there is one.
+func LoadWithOptions(corpusFilepath string, options Options) (*Corpus, []string, error) {
+	var filter *Filter
+	if options.Where != "" {
+		var err error
+		filter, err = ParseFilter(options.Where)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid filter %s: %w", options.Where, err)
+		}
 	}
 
-	lines := []*line{}
-	people := map[string]bool{}
-
-	bytes, err := ioutil.ReadFile(corpusFilepath)
+	filepaths, err := corpusFiles(corpusFilepath, options.Format)
 	if err != nil {
 		return nil, nil, fmt.Errorf("error loading corpus: %w", err)
 	}
 
-	err = json.Unmarshal(bytes, &lines)
-	if err != nil {
-		return nil, nil, fmt.Errorf("error loading corpus: %w", err)
+	person := options.Person
+	people := map[string]bool{}
+	read := false
+	personFound := false
+	cor := &Corpus{Lines: []string{}, Attributed: []*Line{}}
+	for _, path := range filepaths {
+		err = readFile(path, options, func(line *Line) error {
+			read = true
+			matches := filter == nil || filter.Match(line)
+			if matches {
+				people[strings.ToLower(line.Person)] = true
+			}
+			if person != "" && strings.ToLower(person) != strings.ToLower(line.Person) {
+				return nil
+			}
+
+			personFound = true
+			if matches {
+				cor.Lines = append(cor.Lines, line.Line)
+				cor.Attributed = append(cor.Attributed, line)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("error loading corpus file %s: %w", path, err)
+		}
 	}
 
-	if len(lines) == 0 {
+	if !read {
 		return nil, nil, fmt.Errorf("corpus contains no lines")
 	}
 
-	lineStrs := []string{}
-	for _, line := range lines {
-		people[strings.ToLower(line.Person)] = true
-		if person == "" || strings.ToLower(person) == strings.ToLower(line.Person) {
-			lineStrs = append(lineStrs, line.Line)
-		}
+	if !personFound {
+		return nil, nil, fmt.Errorf("person %s not found in corpus", person)
 	}
 
-	if len(lineStrs) == 0 {
-		return nil, nil, fmt.Errorf("person %s not found in corpus", person)
+	if len(cor.Lines) == 0 {
+		return nil, nil, fmt.Errorf("no lines in corpus match %s", filter)
 	}
 
 	peopleStrs := []string{}
@@ -58,5 +142,198 @@ func Load(corpusFilepath string, person string) (*Corpus, []string, error) {
 	}
 	sort.Strings(peopleStrs)
 
-	return &Corpus{Lines: lineStrs}, peopleStrs, nil
+	return cor, peopleStrs, nil
+}
+
+// Field is the value of a field of the line, by name, ignoring case - "line",
+// "person", "scene", "source", "file" (the file name of the source), "start" and
+// "end" (in seconds), or any of its Metadata. ok is false if the line doesn't
+// have the field.
+func (l *Line) Field(name string) (value string, ok bool) {
+	switch strings.ToLower(name) {
+	case "line":
+		return l.Line, true
+	case "person":
+		return l.Person, true
+	case "scene":
+		return l.Scene, l.Scene != ""
+	case "source":
+		return l.Source, l.Source != ""
+	case "file":
+		return filepath.Base(l.Source), l.Source != ""
+	case "start":
+		return strconv.FormatFloat(l.Start.Seconds(), 'f', -1, 64), l.Start != 0 || l.End != 0
+	case "end":
+		return strconv.FormatFloat(l.End.Seconds(), 'f', -1, 64), l.Start != 0 || l.End != 0
+	}
+
+	value, ok = l.Metadata[strings.ToLower(name)]
+	return value, ok
+}
+
+// UnmarshalJSON reads a line from a JSON object with a "Line", and maybe a
+// "Person" and a "Scene" - any other fields go in the Metadata
+func (l *Line) UnmarshalJSON(data []byte) error {
+	fields := map[string]json.RawMessage{}
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+
+	for name, raw := range fields {
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+		var value interface{}
+		err = decoder.Decode(&value)
+		if err != nil {
+			return err
+		}
+
+		text := ""
+		switch v := value.(type) {
+		case nil:
+		case string:
+			text = v
+		case json.Number:
+			text = v.String()
+		case bool:
+			text = strconv.FormatBool(v)
+		default:
+			text = string(raw)
+		}
+
+		switch name = strings.ToLower(name); name {
+		case "line", "person", "scene":
+			if _, ok := value.(string); !ok && value != nil {
+				return fmt.Errorf("%s must be a string, not %s", name, raw)
+			}
+			if name == "line" {
+				l.Line = text
+			} else if name == "person" {
+				l.Person = text
+			} else {
+				l.Scene = text
+			}
+		default:
+			if l.Metadata == nil {
+				l.Metadata = map[string]string{}
+			}
+			l.Metadata[name] = text
+		}
+	}
+
+	return nil
+}
+
+// readFile reads the lines of a corpus file, passing each one to emit with the
+// file as its Source. Lines without a "season" or an "episode" get them from a
+// file name like "tng_s07e12.srt".
+func readFile(path string, options Options, emit func(*Line) error) error {
+	format, err := chooseFormat(path, options.Format)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	season, episode := "", ""
+	match := episodeName.FindStringSubmatch(filepath.Base(path))
+	if match != nil {
+		n, _ := strconv.Atoi(match[1])
+		season = strconv.Itoa(n)
+		n, _ = strconv.Atoi(match[2])
+		episode = strconv.Itoa(n)
+	}
+
+	return format.Read(file, options, func(line *Line) error {
+		line.Source = path
+		if match != nil {
+			if line.Metadata == nil {
+				line.Metadata = map[string]string{}
+			}
+			if _, ok := line.Metadata["season"]; !ok {
+				line.Metadata["season"] = season
+			}
+			if _, ok := line.Metadata["episode"]; !ok {
+				line.Metadata["episode"] = episode
+			}
+		}
+		return emit(line)
+	})
+}
+
+// corpusFiles finds the files of a corpus, in order. A directory has every file
+// in it and its subdirectories that has the extension of a format, or every file
+// if the format is named, leaving out hidden files. A glob pattern has every
+// file matching it, and any other path is just that file.
+func corpusFiles(corpusFilepath string, format string) ([]string, error) {
+	info, err := os.Stat(corpusFilepath)
+	if err == nil && info.IsDir() {
+		filepaths := []string{}
+		err = filepath.Walk(corpusFilepath, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			hidden := path != corpusFilepath && strings.HasPrefix(info.Name(), ".")
+			if info.IsDir() {
+				if hidden {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			_, known := extensions[strings.ToLower(filepath.Ext(path))]
+			if !hidden && (known || format != "") {
+				filepaths = append(filepaths, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(filepaths) == 0 {
+			return nil, fmt.Errorf("no corpus files in directory %s", corpusFilepath)
+		}
+
+		return filepaths, nil
+	}
+
+	if err != nil && strings.ContainsAny(corpusFilepath, "*?[") {
+		filepaths, err := filepath.Glob(corpusFilepath)
+		if err != nil {
+			return nil, err
+		}
+		if len(filepaths) == 0 {
+			return nil, fmt.Errorf("no corpus files match %s", corpusFilepath)
+		}
+
+		sort.Strings(filepaths)
+		return filepaths, nil
+	}
+
+	return []string{corpusFilepath}, nil
+}
+
+// chooseFormat finds the format by name, or by the extension of the corpus file
+// if name is empty. A file with an unknown extension is read as JSON.
+func chooseFormat(corpusFilepath string, name string) (*Format, error) {
+	if name != "" {
+		format, ok := formats[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown corpus format %s (expected one of %s)", name, strings.Join(Formats(), ", "))
+		}
+		return format, nil
+	}
+
+	format, ok := extensions[strings.ToLower(filepath.Ext(corpusFilepath))]
+	if !ok {
+		return formats["json"], nil
+	}
+
+	return format, nil
 }
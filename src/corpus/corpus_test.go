@@ -0,0 +1,446 @@
+package corpus
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Load(t *testing.T) {
+	cor, people, err := Load("test_corpus.json", "")
+	if err != nil {
+		t.Fatalf("Error loading corpus: %v", err)
+	}
+	if len(cor.Lines) != 21 || !reflect.DeepEqual([]string{"al"}, people) {
+		t.Errorf("expected 21 lines by al, got %d lines by %v", len(cor.Lines), people)
+	}
+
+	_, _, err = Load("test_corpus.json", "nobody")
+	if err == nil {
+		t.Errorf("expected error loading lines by a missing person")
+	}
+}
+
+func Test_LoadWithOptions(t *testing.T) {
+	expected := []struct {
+		filepath string
+		options  Options
+		lines    []string
+		people   []string
+	}{
+		{
+			"test_corpus.txt",
+			Options{},
+			[]string{"Engage.", "Make it so, Number One.", "The ship hums: quietly and low.", "Hold still.", "Remember: the prime directive.", "Note: stardate 4523."},
+			[]string{"", "dr. crusher", "picard", "riker"},
+		},
+		{
+			"test_corpus.txt",
+			Options{Person: "riker"},
+			[]string{"Make it so, Number One."},
+			[]string{"", "dr. crusher", "picard", "riker"},
+		},
+		{
+			"test_corpus.csv",
+			Options{LineColumn: "text", PersonColumn: "speaker"},
+			[]string{"Tea, Earl Grey, hot.", "Shields up."},
+			[]string{"picard", "riker"},
+		},
+		{
+			"test_corpus.tsv",
+			Options{},
+			[]string{"He said \"engage\" twice.", "\"Make it so,\" he said.", "\"Aye\"", "Intriguing."},
+			[]string{"data", "picard", "riker"},
+		},
+		{
+			"test_corpus.jsonl",
+			Options{Person: "Data"},
+			[]string{"Intriguing."},
+			[]string{"data", "picard"},
+		},
+		{
+			// the format overrides the file extension
+			"test_corpus.tsv",
+			Options{Format: "text"},
+			[]string{"person\tline", "Picard\tHe said \"engage\" twice.", "Picard\t\"Make it so,\" he said.", "Riker\t\"Aye\"", "Data\tIntriguing."},
+			[]string{""},
+		},
+	}
+
+	for _, e := range expected {
+		cor, people, err := LoadWithOptions(e.filepath, e.options)
+		if err != nil {
+			t.Errorf("Error loading %s: %v", e.filepath, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(e.lines, cor.Lines) {
+			t.Errorf("expected lines %q from %s, got %q", e.lines, e.filepath, cor.Lines)
+		}
+		if !reflect.DeepEqual(e.people, people) {
+			t.Errorf("expected people %q from %s, got %q", e.people, e.filepath, people)
+		}
+	}
+
+	_, _, err := LoadWithOptions("test_corpus.csv", Options{})
+	if err == nil {
+		t.Errorf("expected error loading csv without a line column")
+	}
+	_, _, err = LoadWithOptions("test_corpus.csv", Options{LineColumn: "text", PersonColumn: "character"})
+	if err == nil {
+		t.Errorf("expected error loading csv without the person column")
+	}
+	_, _, err = LoadWithOptions("test_corpus.txt", Options{Format: "yaml"})
+	if err == nil {
+		t.Errorf("expected error loading an unknown format")
+	}
+}
+
+func Test_RegisterFormat(t *testing.T) {
+	RegisterFormat(&Format{Name: "shout", Extensions: []string{".shout"}, Read: func(r io.Reader, options Options, emit func(*Line) error) error {
+		return eachLine(r, func(number int, text string) error {
+			return emit(&Line{Line: strings.ToUpper(text)})
+		})
+	}})
+	defer delete(formats, "shout")
+	defer delete(extensions, ".shout")
+
+	found := false
+	for _, name := range Formats() {
+		found = found || name == "shout"
+	}
+	if !found {
+		t.Errorf("expected shout in formats %v", Formats())
+	}
+
+	format, err := chooseFormat("lines.SHOUT", "")
+	if err != nil || format.Name != "shout" {
+		t.Errorf("expected the shout format for a .SHOUT file, got %v (%v)", format, err)
+	}
+
+	lines := []*Line{}
+	err = format.Read(strings.NewReader("hello\nthere"), Options{}, func(line *Line) error {
+		lines = append(lines, line)
+		return nil
+	})
+	if err != nil || len(lines) != 2 || lines[1].Line != "THERE" {
+		t.Errorf("unexpected lines %v (%v)", lines, err)
+	}
+}
+
+func Test_personName(t *testing.T) {
+	expected := map[string]bool{
+		"PICARD":         true,
+		"DR. CRUSHER":    true,
+		"Q":              true,
+		"2ND OFFICER":    true,
+		"LT. CMDR. DATA": true,
+		"Dr. Crusher":    false,
+		"Remember":       false,
+		"The ship hums":  false,
+		"A B C D":        false,
+		"4523":           false,
+	}
+	for name, person := range expected {
+		if personName(name) != person {
+			t.Errorf("expected %t for \"%s\", got %t", person, name, personName(name))
+		}
+	}
+}
+
+func Test_readScreenplay(t *testing.T) {
+	expected := []struct {
+		options Options
+		lines   []*Line
+	}{
+		{
+			Options{},
+			[]*Line{
+				{Person: "RIKER", Line: "Captain, the readings are off the scale."},
+				{Person: "PICARD", Line: "Then we go in slowly. Mr. Data, all stop on my mark."},
+				{Person: "DATA", Line: "Aye, sir."},
+				{Person: "PICARD", Line: "Engage."},
+			},
+		},
+		{
+			Options{StageDirections: true, Scenes: true},
+			[]*Line{
+				{Line: "The Enterprise holds position near a pulsing nebula. Riker steps down to the command chairs.", Scene: "INT. MAIN BRIDGE - ON VIEWSCREEN"},
+				{Person: "RIKER", Line: "Captain, the readings are off the scale.", Scene: "INT. MAIN BRIDGE - ON VIEWSCREEN"},
+				{Person: "PICARD", Line: "Then we go in slowly. Mr. Data, all stop on my mark.", Scene: "INT. MAIN BRIDGE - ON VIEWSCREEN"},
+				{Person: "DATA", Line: "Aye, sir.", Scene: "INT. MAIN BRIDGE - ON VIEWSCREEN"},
+				{Line: "It drifts into the glowing cloud.", Scene: "EXT. SPACE - THE ENTERPRISE"},
+				{Person: "PICARD", Line: "Engage.", Scene: "EXT. SPACE - THE ENTERPRISE"},
+			},
+		},
+	}
+
+	for _, e := range expected {
+		file, err := os.Open("test_corpus.script")
+		if err != nil {
+			t.Fatalf("Error opening screenplay: %v", err)
+		}
+		lines := []*Line{}
+		err = readScreenplay(file, e.options, func(line *Line) error {
+			lines = append(lines, line)
+			return nil
+		})
+		file.Close()
+		if err != nil {
+			t.Fatalf("Error reading screenplay: %v", err)
+		}
+
+		if len(lines) != len(e.lines) {
+			t.Errorf("expected %d lines with %+v, got %d", len(e.lines), e.options, len(lines))
+			for _, line := range lines {
+				t.Logf("%+v", line)
+			}
+			continue
+		}
+		for i := range lines {
+			if !reflect.DeepEqual(lines[i], e.lines[i]) {
+				t.Errorf("expected line %+v with %+v, got %+v", e.lines[i], e.options, lines[i])
+			}
+		}
+	}
+
+	cor, people, err := LoadWithOptions("test_corpus.script", Options{Person: "picard", Scenes: true})
+	if err != nil {
+		t.Fatalf("Error loading screenplay: %v", err)
+	}
+	if !reflect.DeepEqual([]string{"data", "picard", "riker"}, people) {
+		t.Errorf("expected people [data picard riker], got %v", people)
+	}
+	if len(cor.Lines) != 2 || cor.Attributed[1].Scene != "EXT. SPACE - THE ENTERPRISE" {
+		t.Errorf("unexpected lines by picard %q", cor.Lines)
+	}
+}
+
+func Test_characterCue(t *testing.T) {
+	expected := map[string]bool{
+		"PICARD":         true,
+		"DATA (V.O.)":    true,
+		"DR. CRUSHER":    true,
+		"RIKER (CONT'D)": true,
+		"Riker":          false,
+		"CUT TO:":        false,
+		"(beat)":         false,
+		"A B C D E":      false,
+	}
+	for text, cue := range expected {
+		if characterCue(text) != cue {
+			t.Errorf("expected %t for \"%s\", got %t", cue, text, characterCue(text))
+		}
+	}
+}
+
+func Test_readSubtitles(t *testing.T) {
+	expected := map[string][]*Line{
+		"test_corpus.srt": {
+			{Person: "PICARD", Line: "Tea, Earl Grey, hot.", Start: time.Second, End: 4200 * time.Millisecond},
+			{Person: "RIKER", Line: "Captain?", Start: 65 * time.Second, End: 67 * time.Second},
+			{Line: "Not now, Number One.", Start: 65 * time.Second, End: 67 * time.Second},
+			{Line: "Computer, lights & music.", Start: 68 * time.Second, End: 69500 * time.Millisecond},
+		},
+		"test_corpus.vtt": {
+			{Person: "Data", Line: "I have been attempting to understand the nature of humor.", Start: time.Second, End: 5 * time.Second},
+			{Person: "Worf", Line: "Today is a good day to die!", Start: 6 * time.Second, End: 7 * time.Second},
+			{Person: "Riker", Line: "Not today, Worf.", Start: 6 * time.Second, End: 7 * time.Second},
+		},
+	}
+
+	for filepath, lines := range expected {
+		cor, _, err := Load(filepath, "")
+		if err != nil {
+			t.Fatalf("Error loading %s: %v", filepath, err)
+		}
+
+		if len(cor.Attributed) != len(lines) {
+			t.Errorf("expected %d lines from %s, got %d", len(lines), filepath, len(cor.Attributed))
+			for _, line := range cor.Attributed {
+				t.Logf("%+v", line)
+			}
+			continue
+		}
+		for i := range lines {
+			lines[i].Source = filepath
+			if !reflect.DeepEqual(cor.Attributed[i], lines[i]) {
+				t.Errorf("expected line %+v from %s, got %+v", lines[i], filepath, cor.Attributed[i])
+			}
+		}
+	}
+}
+
+func Test_parseTimestamp(t *testing.T) {
+	expected := map[string]time.Duration{
+		"00:00:01,000":  time.Second,
+		"01:02:03,456":  time.Hour + 2*time.Minute + 3456*time.Millisecond,
+		"02:03.456":     2*time.Minute + 3456*time.Millisecond,
+		"100:00:00.001": 100*time.Hour + time.Millisecond,
+	}
+	for timestamp, duration := range expected {
+		if parseTimestamp(timestamp) != duration {
+			t.Errorf("expected %v for %s, got %v", duration, timestamp, parseTimestamp(timestamp))
+		}
+	}
+}
+
+func Test_LoadWithOptions_files(t *testing.T) {
+	expected := []struct {
+		path    string
+		options Options
+		sources []string
+		people  []string
+	}{
+		{
+			"test_corpus_dir",
+			Options{},
+			[]string{"test_corpus_dir/s01e01.txt", "test_corpus_dir/s01e01.txt", "test_corpus_dir/s01e02.jsonl", "test_corpus_dir/season2/s02e01.txt"},
+			[]string{"data", "picard", "riker", "worf"},
+		},
+		{
+			"test_corpus_dir",
+			Options{Format: "text"},
+			[]string{"test_corpus_dir/notes.md", "test_corpus_dir/s01e01.txt", "test_corpus_dir/s01e01.txt", "test_corpus_dir/s01e02.jsonl", "test_corpus_dir/season2/s02e01.txt"},
+			[]string{"", "picard", "riker", "worf"},
+		},
+		{
+			"test_corpus_dir/s*/s0*.txt",
+			Options{},
+			[]string{"test_corpus_dir/season2/s02e01.txt"},
+			[]string{"worf"},
+		},
+		{
+			"test_corpus_dir/s01e0?.*",
+			Options{Person: "data"},
+			[]string{"test_corpus_dir/s01e02.jsonl"},
+			[]string{"data", "picard", "riker"},
+		},
+	}
+
+	for _, e := range expected {
+		cor, people, err := LoadWithOptions(e.path, e.options)
+		if err != nil {
+			t.Errorf("Error loading %s: %v", e.path, err)
+			continue
+		}
+
+		sources := []string{}
+		for _, line := range cor.Attributed {
+			sources = append(sources, filepath.ToSlash(line.Source))
+		}
+		if !reflect.DeepEqual(e.sources, sources) {
+			t.Errorf("expected sources %v for %s, got %v", e.sources, e.path, sources)
+		}
+		if !reflect.DeepEqual(e.people, people) {
+			t.Errorf("expected people %v for %s, got %v", e.people, e.path, people)
+		}
+	}
+
+	for _, path := range []string{"test_corpus_dir/*.yaml", "test_corpus_dir/.drafts/missing.txt", "test_corpus.txt/["} {
+		_, _, err := Load(path, "")
+		if err == nil {
+			t.Errorf("expected error loading %s", path)
+		}
+	}
+}
+
+func Test_readJSON(t *testing.T) {
+	lines := []*Line{}
+	emit := func(line *Line) error {
+		lines = append(lines, line)
+		return nil
+	}
+
+	err := readJSON(strings.NewReader(`[{"Person": "Q", "Line": "Mon capitaine."}, {"Line": "Hello"}]`), Options{}, emit)
+	if err != nil || len(lines) != 2 || lines[0].Person != "Q" || lines[1].Line != "Hello" {
+		t.Errorf("unexpected lines %v (%v)", lines, err)
+	}
+
+	for _, text := range []string{`{"Line": "not an array"}`, `[{"Line": "unfinished"}`, `[{"Line": 5}]`} {
+		err = readJSON(strings.NewReader(text), Options{}, emit)
+		if err == nil {
+			t.Errorf("expected error reading %s", text)
+		}
+	}
+
+	stop := fmt.Errorf("stop")
+	err = readJSON(strings.NewReader(`[{"Line": "one"}, {"Line": "two"}]`), Options{}, func(line *Line) error {
+		return stop
+	})
+	if err != stop {
+		t.Errorf("expected the error from emit, got %v", err)
+	}
+}
+
+func Test_LoadWithOptions_where(t *testing.T) {
+	expected := []struct {
+		path    string
+		options Options
+		lines   []string
+	}{
+		{"test_corpus_dir", Options{Where: "season == 1 && person != picard"}, []string{"Aye, sir.", "Fascinating."}},
+		{"test_corpus_dir", Options{Person: "riker", Where: "episode = 1"}, []string{"Aye, sir."}},
+		{"test_corpus_dir", Options{Where: "season >= 2 || file ~ 'e02'"}, []string{"Fascinating.", "Today is a good day to die."}},
+		{"test_corpus.csv", Options{LineColumn: "text", Where: "stardate < 41154"}, []string{"Tea, Earl Grey, hot."}},
+		{"test_corpus.jsonl", Options{Where: "season == 7 && canon == true && writers ~ moore"}, []string{"Intriguing."}},
+		{"test_corpus.jsonl", Options{Where: "date == ''"}, []string{"Intriguing."}},
+	}
+
+	for _, e := range expected {
+		cor, _, err := LoadWithOptions(e.path, e.options)
+		if err != nil {
+			t.Errorf("Error loading %s where %s: %v", e.path, e.options.Where, err)
+			continue
+		}
+		if !reflect.DeepEqual(e.lines, cor.Lines) {
+			t.Errorf("expected lines %q from %s where %s, got %q", e.lines, e.path, e.options.Where, cor.Lines)
+		}
+	}
+
+	_, people, err := LoadWithOptions("test_corpus_dir", Options{Person: "data", Where: "season == 1 && person != picard"})
+	if err != nil || !reflect.DeepEqual([]string{"data", "riker"}, people) {
+		t.Errorf("expected people [data riker] where season == 1 && person != picard, got %v (%v)", people, err)
+	}
+
+	for _, options := range []Options{{Where: "season >="}, {Where: "season == 9"}, {Person: "q", Where: "season == 1"}} {
+		_, _, err := LoadWithOptions("test_corpus_dir", options)
+		if err == nil {
+			t.Errorf("expected error loading lines by \"%s\" where %s", options.Person, options.Where)
+		}
+	}
+
+	cor, _, err := LoadWithOptions("test_corpus.csv", Options{LineColumn: "text", PersonColumn: "speaker"})
+	if err != nil {
+		t.Fatalf("Error loading csv: %v", err)
+	}
+	if !reflect.DeepEqual(map[string]string{"stardate": "41153.7"}, cor.Attributed[0].Metadata) || cor.Attributed[1].Metadata != nil {
+		t.Errorf("unexpected metadata %v, %v", cor.Attributed[0].Metadata, cor.Attributed[1].Metadata)
+	}
+}
+
+func Test_Line_UnmarshalJSON(t *testing.T) {
+	line := &Line{}
+	err := json.Unmarshal([]byte(`{"line": "Engage.", "PERSON": "Picard", "Scene": "Bridge", "Season": 7, "Tags": ["a", 1]}`), line)
+	if err != nil {
+		t.Fatalf("Error reading line: %v", err)
+	}
+
+	expected := &Line{Line: "Engage.", Person: "Picard", Scene: "Bridge", Metadata: map[string]string{"season": "7", "tags": `["a", 1]`}}
+	if !reflect.DeepEqual(expected, line) {
+		t.Errorf("expected %+v, got %+v", expected, line)
+	}
+
+	for _, text := range []string{`{"Line": 5}`, `{"Person": ["Picard"]}`, `["Engage."]`} {
+		err = json.Unmarshal([]byte(text), &Line{})
+		if err == nil {
+			t.Errorf("expected error reading %s", text)
+		}
+	}
+}
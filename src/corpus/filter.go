@@ -0,0 +1,417 @@
+package corpus
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Filter picks out lines of a corpus by their fields (see Line.Field), like
+// `season >= 3 && person in ("picard", "riker")`. Comparisons are joined with
+// && and ||, negated with !, and grouped with parentheses. == (or =) and != are
+// equal or not, ignoring case, or as numbers if both values are plain decimals
+// like 7 or -1.5. <, <=, > and >= compare as numbers if both values are, and
+// alphabetically if not. in is equal to any of a list of values in parentheses.
+// ~ and !~ are matching a regular expression or not, ignoring case. Values are
+// numbers, strings in double or single quotes, or single words. A comparison
+// with a field the line doesn't have is false.
+type Filter struct {
+	expression string
+	root       filterNode
+}
+
+// filterNode is part of a parsed filter expression
+type filterNode interface {
+	match(line *Line) bool
+}
+
+type andNode struct {
+	left, right filterNode
+}
+
+type orNode struct {
+	left, right filterNode
+}
+
+type notNode struct {
+	node filterNode
+}
+
+// comparison compares a field of a line with one or more values
+type comparison struct {
+	field   string
+	op      string
+	values  []string
+	pattern *regexp.Regexp
+}
+
+// a token of a filter expression, and where it starts
+type filterToken struct {
+	text     string
+	quoted   bool
+	position int
+}
+
+// the operators of filter expressions, longest first so that "<=" isn't read as
+// "<" and "="
+var filterOperators = []string{"&&", "||", "==", "!=", "<=", ">=", "!~", "!", "=", "<", ">", "~", "(", ")", ","}
+
+// ParseFilter parses a filter expression (see Filter)
+func ParseFilter(expression string) (*Filter, error) {
+	tokens, err := tokenizeFilter(expression)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty filter")
+	}
+
+	p := &filterParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, p.unexpected()
+	}
+
+	return &Filter{expression: expression, root: root}, nil
+}
+
+// Match checks whether a line passes the filter
+func (f *Filter) Match(line *Line) bool {
+	return f.root.match(line)
+}
+
+func (f *Filter) String() string {
+	return f.expression
+}
+
+func (n *andNode) match(line *Line) bool {
+	return n.left.match(line) && n.right.match(line)
+}
+
+func (n *orNode) match(line *Line) bool {
+	return n.left.match(line) || n.right.match(line)
+}
+
+func (n *notNode) match(line *Line) bool {
+	return !n.node.match(line)
+}
+
+func (c *comparison) match(line *Line) bool {
+	value, ok := line.Field(c.field)
+	if !ok {
+		return false
+	}
+
+	switch c.op {
+	case "~":
+		return c.pattern.MatchString(value)
+	case "!~":
+		return !c.pattern.MatchString(value)
+	case "!=":
+		return !equalValues(value, c.values[0])
+	case "==", "in":
+		for _, v := range c.values {
+			if equalValues(value, v) {
+				return true
+			}
+		}
+		return false
+	}
+
+	order := compareValues(value, c.values[0])
+	switch c.op {
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	case ">":
+		return order > 0
+	}
+	return order >= 0
+}
+
+// a value compared as a number - a plain decimal, so that words like "nan" and
+// "infinity" are compared as text
+var filterNumber = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+// equalValues checks whether two values are the same number, or the same text
+// ignoring case
+func equalValues(a, b string) bool {
+	x, y, ok := numbers(a, b)
+	if ok {
+		return x == y
+	}
+
+	return strings.EqualFold(a, b)
+}
+
+// compareValues orders two values as numbers if they both are, or
+// alphabetically ignoring case
+func compareValues(a, b string) int {
+	x, y, ok := numbers(a, b)
+	if ok {
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// numbers parses two values as numbers, ok only if both are plain decimals
+func numbers(a, b string) (x float64, y float64, ok bool) {
+	if !filterNumber.MatchString(a) || !filterNumber.MatchString(b) {
+		return 0, 0, false
+	}
+
+	x, aErr := strconv.ParseFloat(a, 64)
+	y, bErr := strconv.ParseFloat(b, 64)
+	return x, y, aErr == nil && bErr == nil
+}
+
+// tokenizeFilter splits a filter expression into operators, quoted strings, and
+// words or numbers
+func tokenizeFilter(expression string) ([]*filterToken, error) {
+	tokens := []*filterToken{}
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		if unicode.IsSpace(r) {
+			i++
+			continue
+		}
+
+		if r == '"' || r == '\'' {
+			var text strings.Builder
+			j := i + 1
+			for ; j < len(runes) && runes[j] != r; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				text.WriteRune(runes[j])
+			}
+			if j == len(runes) {
+				return nil, fmt.Errorf("unclosed string at %d", i+1)
+			}
+			tokens = append(tokens, &filterToken{text: text.String(), quoted: true, position: i + 1})
+			i = j + 1
+			continue
+		}
+
+		operator := ""
+		for _, op := range filterOperators {
+			if strings.HasPrefix(string(runes[i:]), op) {
+				operator = op
+				break
+			}
+		}
+		if operator != "" {
+			tokens = append(tokens, &filterToken{text: operator, position: i + 1})
+			i += len([]rune(operator))
+			continue
+		}
+
+		j := i
+		for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || strings.ContainsRune("_.-:", runes[j])) {
+			j++
+		}
+		if j == i {
+			return nil, fmt.Errorf("unexpected %q at %d", r, i+1)
+		}
+		tokens = append(tokens, &filterToken{text: string(runes[i:j]), position: i + 1})
+		i = j
+	}
+
+	return tokens, nil
+}
+
+// filterParser parses the tokens of a filter expression, with || binding less
+// tightly than &&, and && less tightly than !
+type filterParser struct {
+	tokens []*filterToken
+	next   int
+}
+
+func (p *filterParser) done() bool {
+	return p.next >= len(p.tokens)
+}
+
+// peek checks whether the next token is the operator op
+func (p *filterParser) peek(op string) bool {
+	return !p.done() && !p.tokens[p.next].quoted && p.tokens[p.next].text == op
+}
+
+func (p *filterParser) unexpected() error {
+	if p.done() {
+		return fmt.Errorf("unexpected end of filter")
+	}
+
+	token := p.tokens[p.next]
+	return fmt.Errorf("unexpected \"%s\" at %d", token.text, token.position)
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek("||") {
+		p.next++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek("&&") {
+		p.next++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (filterNode, error) {
+	if p.peek("!") {
+		p.next++
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{node: node}, nil
+	}
+
+	if p.peek("(") {
+		p.next++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peek(")") {
+			return nil, p.unexpected()
+		}
+		p.next++
+		return node, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (filterNode, error) {
+	field, err := p.parseWord()
+	if err != nil {
+		return nil, err
+	}
+	if p.done() {
+		return nil, p.unexpected()
+	}
+
+	c := &comparison{field: field, op: p.tokens[p.next].text}
+	if p.tokens[p.next].quoted {
+		return nil, p.unexpected()
+	}
+
+	switch c.op {
+	case "in":
+		p.next++
+		if !p.peek("(") {
+			return nil, p.unexpected()
+		}
+		p.next++
+		for {
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			c.values = append(c.values, value)
+
+			if p.peek(")") {
+				p.next++
+				return c, nil
+			}
+			if !p.peek(",") {
+				return nil, p.unexpected()
+			}
+			p.next++
+		}
+	case "=", "==", "!=", "<", "<=", ">", ">=", "~", "!~":
+		p.next++
+		if c.op == "=" {
+			c.op = "=="
+		}
+	default:
+		return nil, p.unexpected()
+	}
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	c.values = []string{value}
+
+	if c.op == "~" || c.op == "!~" {
+		c.pattern, err = regexp.Compile("(?i)" + value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", value, err)
+		}
+	}
+
+	return c, nil
+}
+
+// parseWord parses a field name
+func (p *filterParser) parseWord() (string, error) {
+	if p.done() || p.tokens[p.next].quoted || !isFilterWord(p.tokens[p.next].text) {
+		return "", p.unexpected()
+	}
+
+	p.next++
+	return p.tokens[p.next-1].text, nil
+}
+
+// parseValue parses a value to compare with - a quoted string, a number, or a
+// word
+func (p *filterParser) parseValue() (string, error) {
+	if p.done() || (!p.tokens[p.next].quoted && !isFilterWord(p.tokens[p.next].text)) {
+		return "", p.unexpected()
+	}
+
+	p.next++
+	return p.tokens[p.next-1].text, nil
+}
+
+// isFilterWord checks whether an unquoted token is a word or number rather than
+// an operator
+func isFilterWord(text string) bool {
+	for _, op := range filterOperators {
+		if text == op {
+			return false
+		}
+	}
+
+	return true
+}
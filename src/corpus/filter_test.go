@@ -0,0 +1,105 @@
+package corpus
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_Filter(t *testing.T) {
+	lines := map[string]*Line{
+		"picard": {Line: "Make it so.", Person: "PICARD", Source: "tng/s07e12.txt", Metadata: map[string]string{"season": "7", "episode": "12", "date": "1994-01-03"}},
+		"riker":  {Line: "Aye, sir.", Person: "Riker", Source: "tng/s03e01.txt", Metadata: map[string]string{"season": "3", "episode": "1"}},
+		"data":   {Line: "Intriguing.", Person: "Data", Scene: "INT. BRIDGE", Start: 61500 * time.Millisecond, End: 63 * time.Second},
+	}
+
+	expected := map[string][]string{
+		`season >= 3 && person in ("picard", "riker")`: {"picard", "riker"},
+		`season > 3`:                       {"picard"},
+		`season = 07`:                      {"picard"},
+		`season != 7`:                      {"riker"},
+		`!(season == 7)`:                   {"data", "riker"},
+		`person == data || scene ~ bridge`: {"data"},
+		`person == 'Data' && start >= 60 && end < 63.5`:     {"data"},
+		`file ~ '^s07' || person == riker && season == 7`:   {"picard"},
+		`(file ~ '^s07' || person == riker) && season == 7`: {"picard"},
+		`line !~ "^make"`:                     {"data", "riker"},
+		`date < "1995" && date >= 1994-01-01`: {"picard"},
+		`Person == "PICARD"`:                  {"picard"},
+		`season == 7.0 && season != 7.5`:      {"picard"},
+		`missing == ""`:                       {},
+	}
+
+	for expression, names := range expected {
+		filter, err := ParseFilter(expression)
+		if err != nil {
+			t.Errorf("Error parsing %s: %v", expression, err)
+			continue
+		}
+
+		matched := map[string]bool{}
+		for name, line := range lines {
+			if filter.Match(line) {
+				matched[name] = true
+			}
+		}
+		if len(matched) != len(names) {
+			t.Errorf("expected %v to match %s, got %v", names, expression, matched)
+			continue
+		}
+		for _, name := range names {
+			if !matched[name] {
+				t.Errorf("expected %v to match %s, got %v", names, expression, matched)
+				break
+			}
+		}
+	}
+}
+
+func Test_ParseFilter_errors(t *testing.T) {
+	for _, expression := range []string{
+		``,
+		`season >=`,
+		`season 3`,
+		`season >= 3 &&`,
+		`(season >= 3`,
+		`season >= 3)`,
+		`person in "picard"`,
+		`person in ("picard" "riker")`,
+		`person == "picard`,
+		`person ~ "("`,
+		`"person" == picard`,
+		`season >= 3 $ 4`,
+	} {
+		_, err := ParseFilter(expression)
+		if err == nil {
+			t.Errorf("expected error parsing %s", expression)
+		}
+	}
+}
+
+func Test_Filter_numbers(t *testing.T) {
+	// "nan" and "infinity" are words, not numbers
+	line := &Line{Line: "Hello, dear.", Person: "Nan", Metadata: map[string]string{"season": "Infinity", "episode": "12"}}
+	expected := map[string]bool{
+		`person == "nan"`:      true,
+		`person in (nan, inf)`: true,
+		`person != NaN`:        false,
+		`season > 100`:         true,
+		`season == infinity`:   true,
+		`season < "inf"`:       false,
+		`episode > 9`:          true,
+		`episode == 12.0`:      true,
+		`episode == "+12"`:     false,
+	}
+
+	for expression, matches := range expected {
+		filter, err := ParseFilter(expression)
+		if err != nil {
+			t.Errorf("Error parsing %s: %v", expression, err)
+			continue
+		}
+		if filter.Match(line) != matches {
+			t.Errorf("expected %t for %s, got %t", matches, expression, filter.Match(line))
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package corpus
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Format reads corpus files of one kind
+type Format struct {
+	// the name of the format, for choosing it by name, e.g. "csv"
+	Name string
+
+	// the file extensions of the format, with the dot, e.g. ".csv"
+	Extensions []string
+
+	// Read reads the lines of a corpus file, passing each one to emit as soon as
+	// it's read, and stopping at the first error emit returns
+	Read func(r io.Reader, options Options, emit func(*Line) error) error
+}
+
+// the registered formats, by name and by file extension
+var formats = map[string]*Format{}
+var extensions = map[string]*Format{}
+
+// the longest a "PERSON:" prefix of a line of a text corpus can be, in words
+const maxPersonWords = 3
+
+// a line of a text corpus starting with the name of the person who said it
+var personPrefix = regexp.MustCompile(`^([\pL\pN][\pL\pN'. -]*):\s+(\S.*)$`)
+
+func init() {
+	RegisterFormat(&Format{Name: "json", Extensions: []string{".json"}, Read: readJSON})
+	RegisterFormat(&Format{Name: "jsonl", Extensions: []string{".jsonl", ".ndjson"}, Read: readJSONLines})
+	RegisterFormat(&Format{Name: "text", Extensions: []string{".txt", ".text"}, Read: readText})
+	RegisterFormat(&Format{Name: "csv", Extensions: []string{".csv"}, Read: func(r io.Reader, options Options, emit func(*Line) error) error {
+		return readColumns(csvRecords(r), options, emit)
+	}})
+	RegisterFormat(&Format{Name: "tsv", Extensions: []string{".tsv", ".tab"}, Read: func(r io.Reader, options Options, emit func(*Line) error) error {
+		return readColumns(tsvRecords(r), options, emit)
+	}})
+}
+
+// RegisterFormat adds a format that corpus files can be read in, replacing any
+// format with the same name or extensions
+func RegisterFormat(format *Format) {
+	formats[strings.ToLower(format.Name)] = format
+	for _, extension := range format.Extensions {
+		extensions[strings.ToLower(extension)] = format
+	}
+}
+
+// Formats returns the names of the registered formats, in order
+func Formats() []string {
+	names := []string{}
+	for name := range formats {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// readJSON reads a JSON array of objects with a "Line" and a "Person", one
+// object at a time (see Line.UnmarshalJSON)
+func readJSON(r io.Reader, options Options, emit func(*Line) error) error {
+	decoder := json.NewDecoder(r)
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected a JSON array of lines")
+	}
+
+	for decoder.More() {
+		line := &Line{}
+		err = decoder.Decode(line)
+		if err != nil {
+			return err
+		}
+
+		err = emit(line)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = decoder.Token()
+	return err
+}
+
+// readJSONLines reads JSON Lines - an object with a "Line" and a "Person" on
+// each line. Blank lines are skipped.
+func readJSONLines(r io.Reader, options Options, emit func(*Line) error) error {
+	return eachLine(r, func(number int, text string) error {
+		if strings.TrimSpace(text) == "" {
+			return nil
+		}
+
+		line := &Line{}
+		err := json.Unmarshal([]byte(text), line)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", number, err)
+		}
+
+		return emit(line)
+	})
+}
+
+// readText reads plain text, one line per line. A line can start with the name
+// of the person who said it, and a colon, like "PICARD: Engage." - a name is up
+// to 3 words of capital letters, numbers, apostrophes, periods and hyphens, so
+// that lines like "Remember: the prime directive." keep their first word. Blank
+// lines are skipped.
+func readText(r io.Reader, options Options, emit func(*Line) error) error {
+	return eachLine(r, func(number int, text string) error {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil
+		}
+
+		line := &Line{Line: text}
+		match := personPrefix.FindStringSubmatch(text)
+		if match != nil && personName(match[1]) {
+			line.Person = strings.TrimSpace(match[1])
+			line.Line = match[2]
+		}
+
+		return emit(line)
+	})
+}
+
+// personName checks whether the start of a line of text looks like the name of
+// a person - up to 3 words with no lowercase letters, like a character cue of a
+// screenplay
+func personName(name string) bool {
+	words := strings.Fields(name)
+	if len(words) > maxPersonWords {
+		return false
+	}
+
+	letters := false
+	for _, r := range name {
+		if unicode.IsLower(r) {
+			return false
+		}
+		letters = letters || unicode.IsLetter(r)
+	}
+
+	return letters
+}
+
+// readColumns reads records of values, with a header record naming the
+// columns. The lines are in the column named by options.LineColumn ("line" by
+// default), and the people in the column named by options.PersonColumn
+// ("person" by default, which can be left out). Any other columns go in the
+// Metadata of the lines, by lowercase name. Column names aren't case sensitive.
+// Records without a line are skipped. eachRecord calls its fn with each record.
+func readColumns(eachRecord func(fn func(record []string) error) error, options Options, emit func(*Line) error) error {
+	lineColumnName := options.LineColumn
+	if lineColumnName == "" {
+		lineColumnName = "line"
+	}
+	personColumnName := options.PersonColumn
+	if personColumnName == "" {
+		personColumnName = "person"
+	}
+
+	var header []string
+	lineColumn := -1
+	personColumn := -1
+	return eachRecord(func(record []string) error {
+		if header == nil {
+			header = record
+			for i, name := range header {
+				name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+				header[i] = strings.ToLower(name)
+				if strings.EqualFold(name, lineColumnName) {
+					lineColumn = i
+				} else if strings.EqualFold(name, personColumnName) {
+					personColumn = i
+				}
+			}
+			if lineColumn == -1 {
+				return fmt.Errorf("missing line column \"%s\"", lineColumnName)
+			}
+			if personColumn == -1 && options.PersonColumn != "" {
+				return fmt.Errorf("missing person column \"%s\"", personColumnName)
+			}
+			return nil
+		}
+
+		line := &Line{}
+		for i, value := range record {
+			value = strings.TrimSpace(value)
+			if i == lineColumn || i == personColumn || i >= len(header) || value == "" {
+				continue
+			}
+			if line.Metadata == nil {
+				line.Metadata = map[string]string{}
+			}
+			line.Metadata[header[i]] = value
+		}
+		if lineColumn < len(record) {
+			line.Line = strings.TrimSpace(record[lineColumn])
+		}
+		if personColumn != -1 && personColumn < len(record) {
+			line.Person = strings.TrimSpace(record[personColumn])
+		}
+		if line.Line == "" {
+			return nil
+		}
+
+		return emit(line)
+	})
+}
+
+// csvRecords reads the records of comma separated values, for readColumns
+func csvRecords(r io.Reader) func(fn func(record []string) error) error {
+	return func(fn func(record []string) error) error {
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			err = fn(record)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// tsvRecords reads the records of tab separated values, one per line, for
+// readColumns. Tabs can't be in values, so there's no quoting - quotes are just
+// part of the text. Blank lines are skipped.
+func tsvRecords(r io.Reader) func(fn func(record []string) error) error {
+	return func(fn func(record []string) error) error {
+		return eachLine(r, func(number int, text string) error {
+			if strings.TrimSpace(text) == "" {
+				return nil
+			}
+
+			return fn(strings.Split(text, "\t"))
+		})
+	}
+}
+
+// eachLine calls fn with each line of r, numbered from 1, without its line
+// ending. Lines can be any length.
+func eachLine(r io.Reader, fn func(number int, text string) error) error {
+	reader := bufio.NewReader(r)
+	for number := 1; ; number++ {
+		text, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if text == "" && err == io.EOF {
+			return nil
+		}
+
+		fnErr := fn(number, strings.TrimRight(text, "\r\n"))
+		if fnErr != nil {
+			return fnErr
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package corpus
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// the most words in a character cue, not counting extensions like "(V.O.)"
+const maxCueWords = 4
+
+// a scene heading, like "INT. BRIDGE - NIGHT"
+var sceneHeading = regexp.MustCompile(`^(INT|EXT|EST|INT\.?/EXT|I/E)[. ]`)
+
+// a transition, like "CUT TO:" or "FADE OUT.", or the heading of an act of a
+// teleplay, like "ACT ONE"
+var transition = regexp.MustCompile(`^(FADE (IN|OUT|TO)|[A-Z ]+ TO:$|THE END|END OF (ACT|TEASER)|(TEASER|TAG|ACT [A-Z]+)$)`)
+
+// the extensions of a character cue, like "(V.O.)" or "(CONT'D)"
+var cueExtension = regexp.MustCompile(`\s*\([^)]*\)`)
+
+func init() {
+	RegisterFormat(&Format{Name: "screenplay", Extensions: []string{".screenplay", ".script"}, Read: readScreenplay})
+}
+
+// readScreenplay reads a screenplay or teleplay in the usual plain text layout,
+// with blank lines between paragraphs. A paragraph starting with a character
+// cue - the character's name in capitals, maybe with extensions like "(V.O.)" -
+// is a line of dialogue, without its parentheticals like "(beat)". Scene
+// headings, act headings and transitions are skipped, and so are stage
+// directions unless options.StageDirections is set, when they're lines without a
+// person. With options.Scenes set, each line gets the scene heading it's under.
+func readScreenplay(r io.Reader, options Options, emit func(*Line) error) error {
+	scene := ""
+	paragraph := []string{}
+
+	flush := func() error {
+		text := paragraph
+		paragraph = []string{}
+
+		// headings and transitions can run straight into the next paragraph
+		for len(text) > 0 && (sceneHeading.MatchString(text[0]) || transition.MatchString(text[0])) {
+			if sceneHeading.MatchString(text[0]) {
+				scene = text[0]
+			}
+			text = text[1:]
+		}
+		if len(text) == 0 {
+			return nil
+		}
+
+		line := &Line{}
+		if len(text) > 1 && characterCue(text[0]) {
+			line.Person = strings.TrimSpace(cueExtension.ReplaceAllString(text[0], ""))
+			line.Line = dialogue(text[1:])
+		} else if options.StageDirections {
+			line.Line = strings.Join(text, " ")
+		}
+		if line.Line == "" {
+			return nil
+		}
+
+		if options.Scenes {
+			line.Scene = scene
+		}
+		return emit(line)
+	}
+
+	err := eachLine(r, func(number int, text string) error {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return flush()
+		}
+
+		paragraph = append(paragraph, text)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return flush()
+}
+
+// characterCue checks whether a line of a screenplay is a character cue - a
+// name of up to 4 words with no lowercase letters, maybe with extensions
+func characterCue(text string) bool {
+	name := strings.TrimSpace(cueExtension.ReplaceAllString(text, ""))
+	words := strings.Fields(name)
+	if len(words) == 0 || len(words) > maxCueWords || strings.HasSuffix(name, ":") {
+		return false
+	}
+
+	letters := false
+	for _, r := range name {
+		if unicode.IsLower(r) {
+			return false
+		}
+		letters = letters || unicode.IsLetter(r)
+	}
+
+	return letters
+}
+
+// dialogue joins the lines of a paragraph of dialogue, leaving out
+// parentheticals, which can run over several lines
+func dialogue(text []string) string {
+	spoken := []string{}
+	parenthetical := false
+	for _, line := range text {
+		if strings.HasPrefix(line, "(") {
+			parenthetical = true
+		}
+		if parenthetical {
+			parenthetical = !strings.HasSuffix(line, ")")
+			continue
+		}
+
+		spoken = append(spoken, line)
+	}
+
+	return strings.Join(spoken, " ")
+}
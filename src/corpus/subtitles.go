@@ -0,0 +1,178 @@
+package corpus
+
+import (
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// the longest pause between two cues of a subtitle file that can still split a
+// line between them
+const maxCueGap = 3 * time.Second
+
+// the timing line of a cue, like "00:01:02,500 --> 00:01:04,000", with WebVTT
+// cue settings after it
+var cueTiming = regexp.MustCompile(`^((?:\d+:)?\d{1,2}:\d{2}[,.]\d{3})\s+-->\s+((?:\d+:)?\d{1,2}:\d{2}[,.]\d{3})`)
+
+// a WebVTT voice tag, like "<v Picard>" or "<v.loud Picard>"
+var voiceTag = regexp.MustCompile(`<v(?:\.[^ >]*)?\s+([^>]+)>`)
+
+// formatting tags, like "<i>", "<font color=red>" or "{\an8}"
+var formattingTag = regexp.MustCompile(`<[^>]*>|\{\\[^}]*\}`)
+
+// descriptions of sounds, like "[door slams]"
+var soundDescription = regexp.MustCompile(`\[[^\]]*\]`)
+
+// punctuation ending a sentence, maybe followed by quotes or brackets
+var sentenceEnd = regexp.MustCompile(`[.!?…]["'”’)\]]*$`)
+
+func init() {
+	RegisterFormat(&Format{Name: "srt", Extensions: []string{".srt"}, Read: readSubtitles})
+	RegisterFormat(&Format{Name: "vtt", Extensions: []string{".vtt"}, Read: readSubtitles})
+}
+
+// a line, or part of a line, of a subtitle cue
+type fragment struct {
+	line *Line
+
+	// whether the fragment starts with a dash, which marks a new speaker
+	dashed bool
+}
+
+// readSubtitles reads SubRip (.srt) or WebVTT (.vtt) subtitles. The text of each
+// cue is stripped of formatting tags and sound descriptions, and a cue with a
+// line for each of two speakers, starting with dashes, is split in two. A line
+// split across cues - one that doesn't end a sentence, followed closely by one
+// that isn't said by someone else - is put back together. The person is taken
+// from a WebVTT voice tag like "<v Picard>", or a "PICARD: " prefix (see
+// readText), and each line gets the start of its first cue and the end of its
+// last. A line is emitted once the next one starts.
+func readSubtitles(r io.Reader, options Options, emit func(*Line) error) error {
+	var last *Line
+	block := []string{}
+
+	add := func(f *fragment) error {
+		if last != nil && !f.dashed {
+			continues := !sentenceEnd.MatchString(last.Line) || strings.HasSuffix(last.Line, "..") || strings.HasSuffix(last.Line, "…")
+			if continues && f.line.Start-last.End <= maxCueGap && (f.line.Person == "" || strings.EqualFold(f.line.Person, last.Person)) {
+				last.Line += " " + strings.TrimLeft(f.line.Line, ".… ")
+				last.End = f.line.End
+				return nil
+			}
+		}
+
+		var err error
+		if last != nil {
+			err = emit(last)
+		}
+		last = f.line
+		return err
+	}
+
+	flush := func() error {
+		fragments := cueFragments(block)
+		block = []string{}
+		for _, f := range fragments {
+			err := add(f)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	err := eachLine(r, func(number int, text string) error {
+		text = strings.TrimSpace(strings.TrimPrefix(text, "\ufeff"))
+		if text == "" {
+			return flush()
+		}
+
+		block = append(block, text)
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		return err
+	}
+
+	if last != nil {
+		return emit(last)
+	}
+	return nil
+}
+
+// cueFragments splits a block of a subtitle file into its fragments of lines.
+// Blocks without a timing line, like the WebVTT header and notes, have none.
+func cueFragments(block []string) []*fragment {
+	timing := -1
+	var start, end time.Duration
+	for i, text := range block {
+		match := cueTiming.FindStringSubmatch(text)
+		if match != nil {
+			timing = i
+			start = parseTimestamp(match[1])
+			end = parseTimestamp(match[2])
+			break
+		}
+	}
+	if timing == -1 {
+		return nil
+	}
+
+	fragments := []*fragment{}
+	person := ""
+	for _, text := range block[timing+1:] {
+		voiced := false
+		if match := voiceTag.FindStringSubmatch(text); match != nil {
+			person = strings.TrimSpace(match[1])
+			voiced = true
+		}
+		text = soundDescription.ReplaceAllString(formattingTag.ReplaceAllString(text, ""), "")
+		text = strings.TrimSpace(html.UnescapeString(text))
+
+		dashed := strings.HasPrefix(text, "-")
+		text = strings.TrimSpace(strings.TrimLeft(text, "-"))
+		if text == "" {
+			continue
+		}
+
+		line := &Line{Line: text, Person: person, Start: start, End: end}
+		named := voiced
+		if match := personPrefix.FindStringSubmatch(text); match != nil && personName(match[1]) {
+			line.Person = strings.TrimSpace(match[1])
+			line.Line = match[2]
+			named = true
+		}
+
+		// the lines of a cue are one fragment, unless a dash or a name starts a
+		// new one
+		if len(fragments) > 0 && !dashed && !named {
+			previous := fragments[len(fragments)-1].line
+			previous.Line += " " + line.Line
+			continue
+		}
+
+		fragments = append(fragments, &fragment{line: line, dashed: dashed})
+	}
+
+	return fragments
+}
+
+// parseTimestamp parses a subtitle timestamp, like "01:02:03,456" or "02:03.456"
+func parseTimestamp(timestamp string) time.Duration {
+	parts := strings.Split(strings.Replace(timestamp, ",", ".", 1), ":")
+
+	var duration time.Duration
+	for _, part := range parts[:len(parts)-1] {
+		n, _ := strconv.Atoi(part)
+		duration = duration*60 + time.Duration(n)
+	}
+
+	seconds, _ := strconv.ParseFloat(parts[len(parts)-1], 64)
+	return duration*time.Minute + time.Duration(seconds*float64(time.Second)+0.5)
+}
@@ -0,0 +1,370 @@
+package form
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/verkestk/goetry/src/meter"
+)
+
+// Form is the definition of a poetic form, as read from a YAML or JSON file.
+//
+// Syllables and Meter each hold either one value for every line of the poem, or
+// a value for each line. Every line needs a syllable count, a meter with a line
+// length (like "iambic pentameter"), or both.
+//
+// Rhyme is the rhyme scheme, with stanzas separated by whitespace. Each line is
+// a letter naming its rhyme group (case doesn't matter) or "-" for a line that
+// doesn't need to rhyme. A letter followed by a number is a refrain - a line
+// repeated word for word everywhere it appears, which also rhymes with the rest
+// of its group. A villanelle starts "A1bA2 abA1 abA2".
+type Form struct {
+	// the name of the form, e.g. "Shakespearean sonnet"
+	Name string `yaml:"name" json:"name"`
+
+	// the number of lines in each stanza. Can be left out if there is a rhyme
+	// scheme, which already describes the stanzas.
+	Stanzas []int `yaml:"stanzas" json:"stanzas"`
+
+	// the number of syllables in the lines
+	Syllables Counts `yaml:"syllables" json:"syllables"`
+
+	// the meter of the lines, e.g. "iambic pentameter"
+	Meter Meters `yaml:"meter" json:"meter"`
+
+	// the number of syllables in a line allowed to conflict with the meter
+	Tolerance int `yaml:"tolerance" json:"tolerance"`
+
+	// whether lines can end with an extra unstressed syllable
+	FeminineEndings bool `yaml:"feminine_endings" json:"feminine_endings"`
+
+	// whether lines can start with the first foot reversed
+	InitialInversion bool `yaml:"initial_inversion" json:"initial_inversion"`
+
+	// whether lines can start with a first foot missing its first unstressed
+	// syllable
+	ShortFirstFoot bool `yaml:"short_first_foot" json:"short_first_foot"`
+
+	// the rhyme scheme, e.g. "ABAB CDCD EFEF GG"
+	Rhyme string `yaml:"rhyme" json:"rhyme"`
+
+	// the minimum strength of the rhymes (defaults to 1)
+	Strength int `yaml:"strength" json:"strength"`
+}
+
+// Counts is a list of syllable counts, which can also be written in a form file
+// as a single count
+type Counts []int
+
+// Meters is a list of meters, which can also be written in a form file as a
+// single meter
+type Meters []string
+
+// UnmarshalYAML reads one count, or a list of them
+func (c *Counts) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var count int
+	if unmarshal(&count) == nil {
+		*c = Counts{count}
+		return nil
+	}
+
+	return unmarshal((*[]int)(c))
+}
+
+// UnmarshalJSON reads one count, or a list of them
+func (c *Counts) UnmarshalJSON(data []byte) error {
+	var count int
+	if json.Unmarshal(data, &count) == nil {
+		*c = Counts{count}
+		return nil
+	}
+
+	return json.Unmarshal(data, (*[]int)(c))
+}
+
+// UnmarshalYAML reads one meter, or a list of them
+func (m *Meters) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var meter string
+	if unmarshal(&meter) == nil {
+		*m = Meters{meter}
+		return nil
+	}
+
+	return unmarshal((*[]string)(m))
+}
+
+// UnmarshalJSON reads one meter, or a list of them
+func (m *Meters) UnmarshalJSON(data []byte) error {
+	var meter string
+	if json.Unmarshal(data, &meter) == nil {
+		*m = Meters{meter}
+		return nil
+	}
+
+	return json.Unmarshal(data, (*[]string)(m))
+}
+
+// Line describes what a single line of a poem must satisfy
+type Line struct {
+	// the number of syllables in the line, 0 if it only needs to fit the meter
+	Syllables int
+
+	// the foot of the meter of the line, nil if the line has no meter
+	Foot *meter.Foot
+
+	// the number of feet in the line, 0 if it only needs a syllable count
+	Feet int
+
+	// the number of syllables allowed to conflict with the meter
+	Tolerance int
+
+	// the departures from the meter allowed without counting as deviations
+	Variations meter.Variations
+
+	// the rhyme group of the line, empty if it doesn't need to rhyme
+	Rhyme string
+
+	// the refrain the line repeats, e.g. "A1", empty if it isn't a refrain
+	Refrain string
+}
+
+// TotalSyllables is the number of syllables a line should have - either the
+// syllable count, or the length of its meter
+func (l *Line) TotalSyllables() int {
+	if l.Syllables > 0 || l.Foot == nil {
+		return l.Syllables
+	}
+
+	return l.Feet * len(l.Foot.Pattern)
+}
+
+// Lengths is every number of syllables a line can have - the TotalSyllables,
+// plus one more if the line can have a feminine ending, and one fewer if it can
+// have a short first foot
+func (l *Line) Lengths() []int {
+	lengths := []int{l.TotalSyllables()}
+	if l.Foot == nil {
+		return lengths
+	}
+
+	if l.Variations.FeminineEnding {
+		lengths = append(lengths, l.TotalSyllables()+1)
+	}
+	if l.Variations.ShortFirstFoot && strings.HasPrefix(l.Foot.Pattern, "0") {
+		lengths = append(lengths, l.TotalSyllables()-1)
+	}
+
+	return lengths
+}
+
+// Load reads a form definition from a file. Files ending in ".json" are read as
+// JSON, and anything else as YAML. Unknown fields are an error, so that a typo
+// isn't silently ignored. The form is validated before it's returned.
+func Load(formFilepath string) (*Form, error) {
+	bytes, err := ioutil.ReadFile(formFilepath)
+	if err != nil {
+		return nil, fmt.Errorf("error loading form: %w", err)
+	}
+
+	form := &Form{}
+	if strings.ToLower(filepath.Ext(formFilepath)) == ".json" {
+		decoder := json.NewDecoder(strings.NewReader(string(bytes)))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(form)
+	} else {
+		err = yaml.UnmarshalStrict(bytes, form)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading form: %w", err)
+	}
+
+	err = form.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid form %s: %w", formFilepath, err)
+	}
+
+	return form, nil
+}
+
+// LoadAll reads every form definition in a directory - the files ending in
+// ".yaml", ".yml" or ".json" - in order of their names
+func LoadAll(directory string) ([]*Form, error) {
+	files, err := ioutil.ReadDir(directory)
+	if err != nil {
+		return nil, fmt.Errorf("error loading forms: %w", err)
+	}
+
+	forms := []*Form{}
+	for _, file := range files {
+		extension := strings.ToLower(filepath.Ext(file.Name()))
+		if file.IsDir() || (extension != ".yaml" && extension != ".yml" && extension != ".json") {
+			continue
+		}
+
+		form, err := Load(filepath.Join(directory, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		forms = append(forms, form)
+	}
+
+	return forms, nil
+}
+
+// Validate checks that the form describes a poem that can be generated
+func (f *Form) Validate() error {
+	_, err := f.Lines()
+	return err
+}
+
+// Lines resolves the form into the constraints for each line of each stanza.
+// Returns an error if the form is invalid.
+func (f *Form) Lines() ([][]*Line, error) {
+	scheme := parseRhymeScheme(f.Rhyme)
+
+	stanzas := f.Stanzas
+	if len(stanzas) == 0 {
+		for _, stanza := range scheme {
+			stanzas = append(stanzas, len(stanza))
+		}
+	}
+	if len(stanzas) == 0 {
+		return nil, fmt.Errorf("form has no stanzas")
+	}
+
+	total := 0
+	for i, count := range stanzas {
+		if count < 1 {
+			return nil, fmt.Errorf("stanza %d has no lines", i+1)
+		}
+		total += count
+	}
+
+	if len(f.Syllables) > 1 && len(f.Syllables) != total {
+		return nil, fmt.Errorf("form has %d lines but %d syllable counts", total, len(f.Syllables))
+	}
+	if len(f.Meter) > 1 && len(f.Meter) != total {
+		return nil, fmt.Errorf("form has %d lines but %d meters", total, len(f.Meter))
+	}
+	if f.Tolerance < 0 {
+		return nil, fmt.Errorf("tolerance can't be negative")
+	}
+	if f.Strength < 0 {
+		return nil, fmt.Errorf("rhyme strength can't be negative")
+	}
+
+	if f.Rhyme != "" {
+		if len(scheme) != len(stanzas) {
+			return nil, fmt.Errorf("form has %d stanzas but the rhyme scheme has %d", len(stanzas), len(scheme))
+		}
+		for i := range scheme {
+			if len(scheme[i]) != stanzas[i] {
+				return nil, fmt.Errorf("stanza %d has %d lines but the rhyme scheme has %d", i+1, stanzas[i], len(scheme[i]))
+			}
+		}
+	}
+
+	lines := [][]*Line{}
+	refrains := map[string]*Line{}
+	index := 0
+	for i, count := range stanzas {
+		stanza := []*Line{}
+		for j := 0; j < count; j++ {
+			line := &Line{
+				Tolerance: f.Tolerance,
+				Variations: meter.Variations{
+					FeminineEnding:   f.FeminineEndings,
+					InitialInversion: f.InitialInversion,
+					ShortFirstFoot:   f.ShortFirstFoot,
+				},
+			}
+
+			if len(f.Syllables) > 0 {
+				line.Syllables = f.Syllables[index%len(f.Syllables)]
+				if line.Syllables < 1 {
+					return nil, fmt.Errorf("line %d needs at least 1 syllable", index+1)
+				}
+			}
+
+			if len(f.Meter) > 0 {
+				foot, feet, err := meter.Parse(f.Meter[index%len(f.Meter)])
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", index+1, err)
+				}
+				line.Foot = &foot
+				line.Feet = feet
+			}
+
+			if line.TotalSyllables() == 0 {
+				return nil, fmt.Errorf("line %d needs a syllable count or a meter with a line length", index+1)
+			}
+
+			if f.Rhyme != "" {
+				token := scheme[i][j]
+				if token != "-" && !unicode.IsLetter(rune(token[0])) {
+					return nil, fmt.Errorf("invalid rhyme scheme \"%s\" for line %d", token, index+1)
+				}
+				if token != "-" {
+					line.Rhyme = strings.ToUpper(token[:1])
+					if len(token) > 1 {
+						line.Refrain = strings.ToUpper(token)
+					}
+				}
+			}
+
+			if line.Refrain != "" {
+				refrain, ok := refrains[line.Refrain]
+				if ok && !sameShape(refrain, line) {
+					return nil, fmt.Errorf("line %d repeats refrain %s with a different syllable count or meter", index+1, line.Refrain)
+				}
+				refrains[line.Refrain] = line
+			}
+
+			stanza = append(stanza, line)
+			index++
+		}
+		lines = append(lines, stanza)
+	}
+
+	return lines, nil
+}
+
+// sameShape checks whether two lines have the same length and meter, so that
+// one can repeat the other
+func sameShape(line1, line2 *Line) bool {
+	if line1.TotalSyllables() != line2.TotalSyllables() {
+		return false
+	}
+
+	if line1.Foot == nil || line2.Foot == nil {
+		return line1.Foot == line2.Foot
+	}
+
+	return *line1.Foot == *line2.Foot
+}
+
+// parseRhymeScheme splits a rhyme scheme into stanzas, and each stanza into the
+// tokens for each line - a letter, a letter followed by a refrain number, or
+// "-". Anything else becomes its own token, so that it fails validation.
+func parseRhymeScheme(rhyme string) [][]string {
+	scheme := [][]string{}
+	for _, stanza := range strings.Fields(rhyme) {
+		tokens := []string{}
+		for _, r := range stanza {
+			if unicode.IsDigit(r) && len(tokens) > 0 && unicode.IsLetter(rune(tokens[len(tokens)-1][0])) {
+				tokens[len(tokens)-1] += string(r)
+			} else {
+				tokens = append(tokens, string(r))
+			}
+		}
+		scheme = append(scheme, tokens)
+	}
+
+	return scheme
+}
@@ -0,0 +1,196 @@
+package form
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/verkestk/goetry/src/meter"
+)
+
+func Test_Load(t *testing.T) {
+	form, err := Load("test_form.yaml")
+	if err != nil {
+		t.Fatalf("Error loading form: %v", err)
+	}
+
+	expected := &Form{
+		Name:      "test form",
+		Syllables: []int{8},
+		Meter:     []string{"iambic"},
+		Tolerance: 1,
+		Rhyme:     "A1bA2 abA1 --",
+		Strength:  2,
+	}
+	if !reflect.DeepEqual(expected, form) {
+		t.Logf("expected: %+v\n", expected)
+		t.Logf("actual: %+v\n", form)
+		t.Errorf("unexpected yaml form")
+	}
+
+	form, err = Load("test_form.json")
+	if err != nil {
+		t.Fatalf("Error loading form: %v", err)
+	}
+
+	expected = &Form{
+		Name:      "test form",
+		Stanzas:   []int{3, 2},
+		Syllables: []int{5, 7, 5, 3, 3},
+	}
+	if !reflect.DeepEqual(expected, form) {
+		t.Logf("expected: %+v\n", expected)
+		t.Logf("actual: %+v\n", form)
+		t.Errorf("unexpected json form")
+	}
+
+	// a single count or meter is for every line
+	for _, formFilepath := range []string{"test_form_scalar.yaml", "test_form_scalar.json"} {
+		form, err = Load(formFilepath)
+		if err != nil {
+			t.Fatalf("Error loading %s: %v", formFilepath, err)
+		}
+
+		expected = &Form{
+			Name:      "scalar form",
+			Stanzas:   []int{2},
+			Syllables: []int{10},
+			Meter:     []string{"iambic pentameter"},
+		}
+		if !reflect.DeepEqual(expected, form) {
+			t.Errorf("expected %+v from %s, got %+v", expected, formFilepath, form)
+		}
+	}
+
+	// a misspelled field is an error, not ignored
+	for _, formFilepath := range []string{"test_form_typo.yaml", "test_form_typo.json"} {
+		_, err = Load(formFilepath)
+		if err == nil {
+			t.Errorf("expected error loading %s", formFilepath)
+		}
+	}
+
+	_, err = Load("missing.yaml")
+	if err == nil {
+		t.Errorf("expected error loading missing form")
+	}
+}
+
+func Test_LoadAll(t *testing.T) {
+	forms, err := LoadAll("../../forms")
+	if err != nil {
+		t.Fatalf("Error loading forms: %v", err)
+	}
+
+	names := []string{}
+	for _, form := range forms {
+		names = append(names, form.Name)
+	}
+	expected := []string{"cinquain", "haiku", "limerick", "Shakespearean sonnet", "tanka", "villanelle"}
+	if !reflect.DeepEqual(expected, names) {
+		t.Errorf("expected forms %v, got %v", expected, names)
+	}
+
+	_, err = LoadAll("missing")
+	if err == nil {
+		t.Errorf("expected error loading missing directory")
+	}
+}
+
+func Test_Form_Lines(t *testing.T) {
+	form, _ := Load("test_form.yaml")
+	stanzas, err := form.Lines()
+	if err != nil {
+		t.Fatalf("Error resolving lines: %v", err)
+	}
+
+	expected := [][]*Line{
+		[]*Line{
+			&Line{Syllables: 8, Foot: &meter.Iamb, Tolerance: 1, Rhyme: "A", Refrain: "A1"},
+			&Line{Syllables: 8, Foot: &meter.Iamb, Tolerance: 1, Rhyme: "B"},
+			&Line{Syllables: 8, Foot: &meter.Iamb, Tolerance: 1, Rhyme: "A", Refrain: "A2"},
+		},
+		[]*Line{
+			&Line{Syllables: 8, Foot: &meter.Iamb, Tolerance: 1, Rhyme: "A"},
+			&Line{Syllables: 8, Foot: &meter.Iamb, Tolerance: 1, Rhyme: "B"},
+			&Line{Syllables: 8, Foot: &meter.Iamb, Tolerance: 1, Rhyme: "A", Refrain: "A1"},
+		},
+		[]*Line{
+			&Line{Syllables: 8, Foot: &meter.Iamb, Tolerance: 1},
+			&Line{Syllables: 8, Foot: &meter.Iamb, Tolerance: 1},
+		},
+	}
+	if !reflect.DeepEqual(expected, stanzas) {
+		for i := range stanzas {
+			for j := range stanzas[i] {
+				t.Logf("actual [%d][%d]: %+v\n", i, j, stanzas[i][j])
+			}
+		}
+		t.Errorf("unexpected lines")
+	}
+
+	form = &Form{Meter: []string{"anapestic trimeter", "anapestic dimeter"}, Stanzas: []int{2}}
+	stanzas, err = form.Lines()
+	if err != nil {
+		t.Fatalf("Error resolving lines: %v", err)
+	}
+	if stanzas[0][0].TotalSyllables() != 9 || stanzas[0][1].TotalSyllables() != 6 {
+		t.Errorf("expected 9 and 6 syllables, got %d and %d", stanzas[0][0].TotalSyllables(), stanzas[0][1].TotalSyllables())
+	}
+}
+
+func Test_Form_Validate(t *testing.T) {
+	invalid := map[string]*Form{
+		"no stanzas":              &Form{Syllables: []int{5}},
+		"empty stanza":            &Form{Stanzas: []int{3, 0}, Syllables: []int{5}},
+		"no length":               &Form{Stanzas: []int{3}},
+		"meter without length":    &Form{Stanzas: []int{3}, Meter: []string{"iambic"}},
+		"too few syllable counts": &Form{Stanzas: []int{3}, Syllables: []int{5, 7}},
+		"too many meters":         &Form{Stanzas: []int{1}, Meter: []string{"iambic dimeter", "iambic dimeter"}},
+		"unknown meter":           &Form{Stanzas: []int{1}, Meter: []string{"sprung rhythm"}},
+		"zero syllables":          &Form{Stanzas: []int{1}, Syllables: []int{0}},
+		"negative tolerance":      &Form{Stanzas: []int{1}, Syllables: []int{5}, Tolerance: -1},
+		"scheme stanza mismatch":  &Form{Stanzas: []int{4}, Syllables: []int{5}, Rhyme: "AB AB"},
+		"scheme line mismatch":    &Form{Stanzas: []int{4, 4}, Syllables: []int{5}, Rhyme: "ABAB ABA"},
+		"invalid scheme":          &Form{Syllables: []int{5}, Rhyme: "AB?B"},
+		"refrain shape mismatch":  &Form{Syllables: []int{5, 7}, Rhyme: "A1 A1"},
+	}
+
+	for name, form := range invalid {
+		if form.Validate() == nil {
+			t.Errorf("expected error validating form with %s", name)
+		}
+	}
+
+	valid := &Form{Syllables: []int{10}, Rhyme: "ABAB CDCD EFEF GG"}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("expected valid form, got %v", err)
+	}
+}
+
+func Test_parseRhymeScheme(t *testing.T) {
+	expected := [][]string{[]string{"A1", "b", "A2"}, []string{"a", "b", "A12"}, []string{"-", "?"}}
+	actual := parseRhymeScheme("A1bA2  abA12\n-?")
+	if !reflect.DeepEqual(expected, actual) {
+		t.Logf("expected: %v\n", expected)
+		t.Logf("actual: %v\n", actual)
+		t.Errorf("unexpected rhyme scheme")
+	}
+}
+
+func Test_Line_Lengths(t *testing.T) {
+	line := &Line{Syllables: 5}
+	if !reflect.DeepEqual([]int{5}, line.Lengths()) {
+		t.Errorf("expected lengths [5], got %v", line.Lengths())
+	}
+
+	line = &Line{Foot: &meter.Anapest, Feet: 3, Variations: meter.Variations{FeminineEnding: true, ShortFirstFoot: true}}
+	if !reflect.DeepEqual([]int{9, 10, 8}, line.Lengths()) {
+		t.Errorf("expected lengths [9 10 8], got %v", line.Lengths())
+	}
+
+	// a trochee has no unstressed syllable to drop from the first foot
+	line = &Line{Foot: &meter.Trochee, Feet: 4, Variations: meter.Variations{ShortFirstFoot: true}}
+	if !reflect.DeepEqual([]int{8}, line.Lengths()) {
+		t.Errorf("expected lengths [8], got %v", line.Lengths())
+	}
+}
@@ -0,0 +1,361 @@
+package meter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Foot is a metrical foot - a repeating unit of stressed ("1") and unstressed
+// ("0") syllables.
+type Foot struct {
+	// the name of the foot, e.g. "iamb"
+	Name string
+
+	// the adjective used to describe a meter of this foot, e.g. "iambic"
+	Adjective string
+
+	// the stresses of the syllables of the foot, e.g. "01"
+	Pattern string
+}
+
+// The standard metrical feet
+var (
+	Iamb    = Foot{Name: "iamb", Adjective: "iambic", Pattern: "01"}
+	Trochee = Foot{Name: "trochee", Adjective: "trochaic", Pattern: "10"}
+	Anapest = Foot{Name: "anapest", Adjective: "anapestic", Pattern: "001"}
+	Dactyl  = Foot{Name: "dactyl", Adjective: "dactylic", Pattern: "100"}
+	Spondee = Foot{Name: "spondee", Adjective: "spondaic", Pattern: "11"}
+)
+
+// Feet is all the standard metrical feet, in order of preference when a line
+// fits more than one foot equally well.
+var Feet = []Foot{Iamb, Trochee, Anapest, Dactyl, Spondee}
+
+var lineLengthNames = []string{"", "monometer", "dimeter", "trimeter", "tetrameter", "pentameter", "hexameter", "heptameter", "octameter"}
+
+// Scansion describes how a line of words fits a meter
+type Scansion struct {
+	// the foot the line was scanned against
+	Foot Foot
+
+	// the number of feet in the line, rounded to the nearest whole foot
+	Feet int
+
+	// the stress pattern chosen for each word in the line
+	Stresses []string
+
+	// the number of syllables whose stress conflicts with the meter
+	Deviation int
+}
+
+// Pattern returns the stress pattern of the whole line
+func (s *Scansion) Pattern() string {
+	pattern := ""
+	for _, stresses := range s.Stresses {
+		pattern += stresses
+	}
+
+	return pattern
+}
+
+// String names the meter of the line, e.g. "iambic pentameter"
+func (s *Scansion) String() string {
+	if s.Feet > 0 && s.Feet < len(lineLengthNames) {
+		return fmt.Sprintf("%s %s", s.Foot.Adjective, lineLengthNames[s.Feet])
+	}
+
+	return fmt.Sprintf("%s (%d feet)", s.Foot.Adjective, s.Feet)
+}
+
+// Scan fits a line of words to a foot. wordStresses holds the alternative
+// stress patterns of each word in the line (as returned by
+// rhymes.Rhymer.LineStresses); the pattern chosen for each word is the one that
+// deviates least from the meter.
+//
+// Monosyllables and syllables with secondary stress ("2") are treated as
+// metrically ambiguous, so they never count as deviations.
+func Scan(wordStresses [][]string, foot Foot) *Scansion {
+	footLength := len(foot.Pattern)
+
+	// dynamic programming over the words, keyed by the position within the foot
+	// where the next word begins. choices[i][phase] records which pattern word i
+	// used, and which phase it started from, for the cheapest path to phase.
+	type choice struct {
+		pattern   int
+		prevPhase int
+	}
+
+	const unreachable = -1
+	costs := make([]int, footLength)
+	for i := range costs {
+		costs[i] = unreachable
+	}
+	costs[0] = 0
+	syllables := make([]int, footLength)
+
+	choices := make([][]choice, len(wordStresses))
+	for i, patterns := range wordStresses {
+		nextCosts := make([]int, footLength)
+		nextSyllables := make([]int, footLength)
+		for phase := range nextCosts {
+			nextCosts[phase] = unreachable
+		}
+		choices[i] = make([]choice, footLength)
+
+		for phase, cost := range costs {
+			if cost == unreachable {
+				continue
+			}
+			for p, pattern := range patterns {
+				nextPhase := (phase + len(pattern)) % footLength
+				nextCost := cost + deviation(pattern, foot.Pattern, phase)
+				if nextCosts[nextPhase] == unreachable || nextCost < nextCosts[nextPhase] {
+					nextCosts[nextPhase] = nextCost
+					nextSyllables[nextPhase] = syllables[phase] + len(pattern)
+					choices[i][nextPhase] = choice{pattern: p, prevPhase: phase}
+				}
+			}
+		}
+
+		costs = nextCosts
+		syllables = nextSyllables
+	}
+
+	bestPhase := -1
+	for phase, cost := range costs {
+		if cost != unreachable && (bestPhase == -1 || cost < costs[bestPhase]) {
+			bestPhase = phase
+		}
+	}
+
+	scansion := &Scansion{Foot: foot, Stresses: make([]string, len(wordStresses))}
+	if bestPhase == -1 {
+		return scansion
+	}
+
+	scansion.Deviation = costs[bestPhase]
+	scansion.Feet = (syllables[bestPhase] + footLength/2) / footLength
+
+	phase := bestPhase
+	for i := len(wordStresses) - 1; i >= 0; i-- {
+		c := choices[i][phase]
+		scansion.Stresses[i] = wordStresses[i][c.pattern]
+		phase = c.prevPhase
+	}
+
+	return scansion
+}
+
+// Variations are departures from a meter that a line is allowed without them
+// counting as deviations
+type Variations struct {
+	// an extra unstressed syllable at the end of the line
+	FeminineEnding bool
+
+	// the first foot reversed, e.g. a trochee starting an iambic line
+	InitialInversion bool
+
+	// the first foot missing its first unstressed syllable, e.g. an iamb starting
+	// an anapestic line
+	ShortFirstFoot bool
+}
+
+// Fit scores a line of words against an exact meter of _feet_ feet, like iambic
+// pentameter. Unlike Scan, the length of the line matters - every syllable
+// missing from or added to the meter counts as a deviation. The allowed
+// variations of the meter are tried too, and the closest fit is returned.
+//
+// As with Scan, monosyllables and syllables with secondary stress are treated as
+// metrically ambiguous.
+func Fit(wordStresses [][]string, foot Foot, feet int, variations Variations) *Scansion {
+	base := strings.Repeat(foot.Pattern, feet)
+	targets := []string{base}
+	if variations.InitialInversion && feet > 0 {
+		targets = append(targets, reverse(foot.Pattern)+base[len(foot.Pattern):])
+	}
+	if variations.ShortFirstFoot && strings.HasPrefix(base, "0") {
+		targets = append(targets, base[1:])
+	}
+	if variations.FeminineEnding {
+		for _, target := range targets {
+			targets = append(targets, target+"0")
+		}
+	}
+
+	var best *Scansion
+	for _, target := range targets {
+		scansion := fit(wordStresses, target)
+		scansion.Foot = foot
+		scansion.Feet = feet
+		if best == nil || scansion.Deviation < best.Deviation {
+			best = scansion
+		}
+	}
+
+	return best
+}
+
+// fit scores a line of words against an exact stress pattern
+func fit(wordStresses [][]string, target string) *Scansion {
+	// dynamic programming over the words, keyed by the number of syllables so
+	// far. choices[i][position] records which pattern word i used, and the
+	// position it started from, for the cheapest path to position.
+	type choice struct {
+		pattern      int
+		prevPosition int
+	}
+
+	costs := map[int]int{0: 0}
+	choices := make([]map[int]choice, len(wordStresses))
+	for i, patterns := range wordStresses {
+		nextCosts := map[int]int{}
+		choices[i] = map[int]choice{}
+
+		for position, cost := range costs {
+			for p, pattern := range patterns {
+				nextPosition := position + len(pattern)
+				nextCost := cost + fitDeviation(pattern, target, position)
+				prevCost, ok := nextCosts[nextPosition]
+				if !ok || nextCost < prevCost || nextCost == prevCost && choiceBefore(position, p, choices[i][nextPosition].prevPosition, choices[i][nextPosition].pattern) {
+					nextCosts[nextPosition] = nextCost
+					choices[i][nextPosition] = choice{pattern: p, prevPosition: position}
+				}
+			}
+		}
+
+		costs = nextCosts
+	}
+
+	// syllables missing from the end of the line are deviations too
+	bestPosition := -1
+	bestCost := 0
+	for position, cost := range costs {
+		if position < len(target) {
+			cost += len(target) - position
+		}
+		if bestPosition == -1 || cost < bestCost || cost == bestCost && position < bestPosition {
+			bestPosition = position
+			bestCost = cost
+		}
+	}
+
+	scansion := &Scansion{Stresses: make([]string, len(wordStresses)), Deviation: bestCost}
+	position := bestPosition
+	for i := len(wordStresses) - 1; i >= 0; i-- {
+		c := choices[i][position]
+		scansion.Stresses[i] = wordStresses[i][c.pattern]
+		position = c.prevPosition
+	}
+
+	return scansion
+}
+
+// choiceBefore breaks ties between equally good choices, so that fit doesn't
+// depend on map iteration order
+func choiceBefore(position1, pattern1, position2, pattern2 int) bool {
+	if position1 == position2 {
+		return pattern1 < pattern2
+	}
+
+	return position1 < position2
+}
+
+// fitDeviation counts the syllables of a word's stress pattern that conflict
+// with the target pattern when the word starts at position, including any that
+// run past the end of it
+func fitDeviation(pattern, target string, position int) int {
+	count := 0
+	for i := 0; i < len(pattern); i++ {
+		if position+i >= len(target) {
+			count++
+		} else if len(pattern) > 1 && !stressMatches(pattern[i], target[position+i]) {
+			count++
+		}
+	}
+
+	return count
+}
+
+func reverse(pattern string) string {
+	reversed := []byte(pattern)
+	for i, j := 0, len(reversed)-1; i < j; i, j = i+1, j-1 {
+		reversed[i], reversed[j] = reversed[j], reversed[i]
+	}
+
+	return string(reversed)
+}
+
+// BestFoot scans a line of words against each of the standard Feet and returns
+// the scansion with the least deviation.
+func BestFoot(wordStresses [][]string) *Scansion {
+	var best *Scansion
+	for _, foot := range Feet {
+		scansion := Scan(wordStresses, foot)
+		if best == nil || scansion.Deviation < best.Deviation {
+			best = scansion
+		}
+	}
+
+	return best
+}
+
+// deviation counts the syllables of a word's stress pattern that conflict with
+// the foot pattern, when the word starts at phase within the foot.
+func deviation(pattern, footPattern string, phase int) int {
+	if len(pattern) < 2 {
+		return 0
+	}
+
+	count := 0
+	for i := 0; i < len(pattern); i++ {
+		expected := footPattern[(phase+i)%len(footPattern)]
+		if !stressMatches(pattern[i], expected) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// stressMatches compares a dictionary stress digit to an expected metrical
+// stress. Secondary stress can fill either position.
+func stressMatches(stress, expected byte) bool {
+	if stress == '2' {
+		return true
+	}
+
+	return stress == expected
+}
+
+// Parse reads the name of a meter, like "iambic pentameter", returning the foot
+// and the number of feet. The foot can be named by its adjective ("iambic") or
+// its name ("iamb"). The line length is optional - if it's left off, the number
+// of feet is 0.
+func Parse(name string) (Foot, int, error) {
+	fields := strings.Fields(strings.ToLower(name))
+	if len(fields) == 0 || len(fields) > 2 {
+		return Foot{}, 0, fmt.Errorf("invalid meter \"%s\"", name)
+	}
+
+	var foot *Foot
+	for i := range Feet {
+		if fields[0] == Feet[i].Name || fields[0] == Feet[i].Adjective {
+			foot = &Feet[i]
+		}
+	}
+	if foot == nil {
+		return Foot{}, 0, fmt.Errorf("unknown metrical foot \"%s\"", fields[0])
+	}
+
+	if len(fields) == 1 {
+		return *foot, 0, nil
+	}
+
+	for feet, lineLengthName := range lineLengthNames {
+		if feet > 0 && fields[1] == lineLengthName {
+			return *foot, feet, nil
+		}
+	}
+
+	return Foot{}, 0, fmt.Errorf("unknown line length \"%s\"", fields[1])
+}
@@ -0,0 +1,196 @@
+package meter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Scan(t *testing.T) {
+	// "a photo opportunity"
+	wordStresses := [][]string{[]string{"0", "1"}, []string{"12"}, []string{"20100"}}
+
+	scansion := Scan(wordStresses, Iamb)
+	if scansion.Deviation != 1 {
+		t.Errorf("expected deviation 1, got %d", scansion.Deviation)
+	}
+	if scansion.Feet != 4 {
+		t.Errorf("expected 4 feet, got %d", scansion.Feet)
+	}
+	expectedStresses := []string{"0", "12", "20100"}
+	if !reflect.DeepEqual(expectedStresses, scansion.Stresses) {
+		t.Logf("expected: %v\n", expectedStresses)
+		t.Logf("actual: %v\n", scansion.Stresses)
+		t.Errorf("unexpected stresses")
+	}
+
+	scansion = Scan(wordStresses, Trochee)
+	if scansion.Deviation != 4 {
+		t.Errorf("expected deviation 4, got %d", scansion.Deviation)
+	}
+
+	// choosing a pronunciation changes which pattern fits: "family" as 100 or 10
+	wordStresses = [][]string{[]string{"100", "10"}, []string{"10"}}
+	scansion = Scan(wordStresses, Trochee)
+	expectedStresses = []string{"10", "10"}
+	if scansion.Deviation != 0 || !reflect.DeepEqual(expectedStresses, scansion.Stresses) {
+		t.Logf("expected: %v\n", expectedStresses)
+		t.Logf("actual: %v (deviation %d)\n", scansion.Stresses, scansion.Deviation)
+		t.Errorf("unexpected trochaic scansion")
+	}
+
+	scansion = Scan(wordStresses, Dactyl)
+	expectedStresses = []string{"100", "10"}
+	if scansion.Deviation != 0 || !reflect.DeepEqual(expectedStresses, scansion.Stresses) {
+		t.Logf("expected: %v\n", expectedStresses)
+		t.Logf("actual: %v (deviation %d)\n", scansion.Stresses, scansion.Deviation)
+		t.Errorf("unexpected dactylic scansion")
+	}
+
+	scansion = Scan([][]string{}, Iamb)
+	if scansion.Deviation != 0 || scansion.Feet != 0 {
+		t.Errorf("expected empty scansion, got deviation %d and %d feet", scansion.Deviation, scansion.Feet)
+	}
+}
+
+func Test_BestFoot(t *testing.T) {
+	lines := map[string][][]string{
+		"iamb":    [][]string{[]string{"01"}, []string{"01"}, []string{"1"}},
+		"trochee": [][]string{[]string{"10"}, []string{"10"}, []string{"1"}},
+		"anapest": [][]string{[]string{"001"}, []string{"001"}},
+		"dactyl":  [][]string{[]string{"100"}, []string{"100"}},
+		"spondee": [][]string{[]string{"11"}, []string{"11"}},
+	}
+
+	for expected, wordStresses := range lines {
+		scansion := BestFoot(wordStresses)
+		if scansion.Foot.Name != expected {
+			t.Errorf("expected best foot %s for %v, got %s", expected, wordStresses, scansion.Foot.Name)
+		}
+	}
+
+	// all monosyllables fit anything, so the first foot wins
+	scansion := BestFoot([][]string{[]string{"1"}, []string{"1"}, []string{"0", "1"}})
+	if scansion.Foot.Name != "iamb" {
+		t.Errorf("expected best foot iamb, got %s", scansion.Foot.Name)
+	}
+}
+
+func Test_Scansion_String(t *testing.T) {
+	scansion := &Scansion{Foot: Iamb, Feet: 5}
+	if scansion.String() != "iambic pentameter" {
+		t.Errorf("expected \"iambic pentameter\", got \"%s\"", scansion.String())
+	}
+
+	scansion = &Scansion{Foot: Dactyl, Feet: 12}
+	if scansion.String() != "dactylic (12 feet)" {
+		t.Errorf("expected \"dactylic (12 feet)\", got \"%s\"", scansion.String())
+	}
+}
+
+func Test_deviation(t *testing.T) {
+	if d := deviation("1", "01", 0); d != 0 {
+		t.Errorf("expected monosyllable deviation 0, got %d", d)
+	}
+	if d := deviation("10", "01", 0); d != 2 {
+		t.Errorf("expected deviation 2, got %d", d)
+	}
+	if d := deviation("10", "01", 1); d != 0 {
+		t.Errorf("expected deviation 0, got %d", d)
+	}
+	if d := deviation("1210", "01", 1); d != 0 {
+		t.Errorf("expected deviation 0, got %d", d)
+	}
+}
+
+func Test_Parse(t *testing.T) {
+	foot, feet, err := Parse("Iambic Pentameter")
+	if err != nil || foot.Name != "iamb" || feet != 5 {
+		t.Errorf("expected iamb with 5 feet, got %s with %d feet (%v)", foot.Name, feet, err)
+	}
+
+	foot, feet, err = Parse("anapest")
+	if err != nil || foot.Name != "anapest" || feet != 0 {
+		t.Errorf("expected anapest with 0 feet, got %s with %d feet (%v)", foot.Name, feet, err)
+	}
+
+	invalid := []string{"", "iambic pentameter please", "amphibrachic trimeter", "iambic eleventy"}
+	for _, name := range invalid {
+		_, _, err = Parse(name)
+		if err == nil {
+			t.Errorf("expected error parsing \"%s\"", name)
+		}
+	}
+}
+
+func Test_Fit(t *testing.T) {
+	// "shall I compare thee to a summer's day"
+	wordStresses := [][]string{[]string{"1"}, []string{"1"}, []string{"01"}, []string{"1"}, []string{"0", "1"}, []string{"0", "1"}, []string{"10"}, []string{"1"}}
+	scansion := Fit(wordStresses, Iamb, 5, Variations{})
+	if scansion.Deviation != 0 {
+		t.Errorf("expected deviation 0, got %d", scansion.Deviation)
+	}
+	if scansion.String() != "iambic pentameter" {
+		t.Errorf("expected iambic pentameter, got %s", scansion)
+	}
+
+	// a syllable short
+	scansion = Fit(wordStresses[:7], Iamb, 5, Variations{})
+	if scansion.Deviation != 1 {
+		t.Errorf("expected deviation 1, got %d", scansion.Deviation)
+	}
+
+	// "summer's day" replaced with "summer evening"
+	wordStresses = [][]string{[]string{"1"}, []string{"1"}, []string{"01"}, []string{"1"}, []string{"0", "1"}, []string{"0", "1"}, []string{"10"}, []string{"10"}}
+	scansion = Fit(wordStresses, Iamb, 5, Variations{})
+	if scansion.Deviation != 1 {
+		t.Errorf("expected deviation 1 without feminine ending, got %d", scansion.Deviation)
+	}
+	scansion = Fit(wordStresses, Iamb, 5, Variations{FeminineEnding: true})
+	if scansion.Deviation != 0 {
+		t.Errorf("expected deviation 0 with feminine ending, got %d", scansion.Deviation)
+	}
+
+	// "summer is come and gone away again": inverted first foot
+	wordStresses = [][]string{[]string{"10"}, []string{"0"}, []string{"1"}, []string{"0"}, []string{"1"}, []string{"01"}, []string{"01"}}
+	scansion = Fit(wordStresses, Iamb, 5, Variations{})
+	if scansion.Deviation != 2 {
+		t.Errorf("expected deviation 2 without initial inversion, got %d", scansion.Deviation)
+	}
+	scansion = Fit(wordStresses, Iamb, 5, Variations{InitialInversion: true})
+	if scansion.Deviation != 0 {
+		t.Errorf("expected deviation 0 with initial inversion, got %d", scansion.Deviation)
+	}
+
+	// "there once was a man from nantucket"
+	wordStresses = [][]string{[]string{"1"}, []string{"1"}, []string{"1", "0"}, []string{"0", "1"}, []string{"1"}, []string{"1", "0"}, []string{"010"}}
+	scansion = Fit(wordStresses, Anapest, 3, Variations{})
+	if scansion.Deviation != 2 {
+		t.Errorf("expected deviation 2 without short first foot or feminine ending, got %d", scansion.Deviation)
+	}
+	scansion = Fit(wordStresses, Anapest, 3, Variations{ShortFirstFoot: true, FeminineEnding: true})
+	if scansion.Deviation != 0 {
+		t.Errorf("expected deviation 0 with short first foot and feminine ending, got %d", scansion.Deviation)
+	}
+
+	// choosing pronunciations to fit the length
+	wordStresses = [][]string{[]string{"100", "10"}, []string{"10"}}
+	scansion = Fit(wordStresses, Trochee, 2, Variations{})
+	expectedStresses := []string{"10", "10"}
+	if scansion.Deviation != 0 || !reflect.DeepEqual(expectedStresses, scansion.Stresses) {
+		t.Logf("expected: %v\n", expectedStresses)
+		t.Logf("actual: %v (deviation %d)\n", scansion.Stresses, scansion.Deviation)
+		t.Errorf("unexpected trochaic fit")
+	}
+}
+
+func Test_fitDeviation(t *testing.T) {
+	if d := fitDeviation("10", "0101", 3); d != 1 {
+		t.Errorf("expected deviation 1 for syllable past the end, got %d", d)
+	}
+	if d := fitDeviation("1", "0101", 0); d != 0 {
+		t.Errorf("expected monosyllable deviation 0, got %d", d)
+	}
+	if d := fitDeviation("1", "0101", 4); d != 1 {
+		t.Errorf("expected deviation 1 for monosyllable past the end, got %d", d)
+	}
+}
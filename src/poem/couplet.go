@@ -0,0 +1,183 @@
+package poem
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/verkestk/goetry/src/form"
+	"github.com/verkestk/goetry/src/rhymes"
+)
+
+// the number of different end words to try building a set of rhyming lines
+// around before giving up
+const maxRhymeCandidates = 100
+
+// Couplets generates _count_ couplets - stanzas of two lines of _syllables_
+// syllables each, whose end words rhyme at at least _strength_. Each line is
+// generated backward from its end word, so the rhyme is guaranteed. No end word
+// is used twice.
+func (g *Generator) Couplets(count, strength, syllables int) (*Poem, error) {
+	poem := &Poem{}
+	used := map[string]bool{}
+
+	line := &form.Line{Syllables: syllables}
+	for len(poem.Stanzas) < count {
+		lines, err := g.rhymingLines([]*form.Line{line, line}, strength, used)
+		if err != nil {
+			return nil, fmt.Errorf("error generating couplet %d: %w", len(poem.Stanzas)+1, err)
+		}
+
+		poem.Stanzas = append(poem.Stanzas, lines)
+	}
+
+	return poem, nil
+}
+
+// rhymingLines generates a line to satisfy each of lines, with end words that
+// all rhyme with each other at at least _strength_. End words in used are
+// avoided, and the end words chosen are added to it. If slant rhymes are
+// allowed (see AllowSlantRhymes), the end words fall back to near rhymes when
+// there aren't enough perfect ones.
+func (g *Generator) rhymingLines(lines []*form.Line, strength int, used map[string]bool) ([]string, error) {
+	generated := g.familyRhymingLines(lines, used, func(word string) [][]string {
+		return g.rhymeFamilies(word, strength, used)
+	})
+	if generated == nil && g.slant > 0 {
+		generated = g.familyRhymingLines(lines, used, func(word string) [][]string {
+			return g.slantFamilies(word, used)
+		})
+	}
+
+	if generated == nil {
+		if g.slant > 0 {
+			return nil, fmt.Errorf("unable to find %d lines ending in words that rhyme at strength %d or slant rhyme at score %g", len(lines), strength, g.slant)
+		}
+		return nil, fmt.Errorf("unable to find %d lines ending in words that rhyme at strength %d", len(lines), strength)
+	}
+
+	return generated, nil
+}
+
+// familyRhymingLines tries the families of random end words until one of them
+// can supply a line for each of lines. Returns nil if none can.
+func (g *Generator) familyRhymingLines(lines []*form.Line, used map[string]bool, families func(word string) [][]string) []string {
+	candidates := g.endWords()
+	tried := 0
+	for _, i := range rand.Perm(len(candidates)) {
+		word := candidates[i]
+		if used[word] {
+			continue
+		}
+
+		if tried == maxRhymeCandidates {
+			break
+		}
+		tried++
+
+		for _, family := range families(word) {
+			if len(family) < len(lines) {
+				continue
+			}
+
+			generated, endWords := g.familyLines(family, lines)
+			if generated != nil {
+				for _, endWord := range endWords {
+					used[endWord] = true
+				}
+				return generated
+			}
+		}
+	}
+
+	return nil
+}
+
+// familyLines generates a line to satisfy each of lines, each ending with a
+// different word from family. Returns the lines and their end words, or nil if
+// the family can't supply enough lines. The first word of the family is always
+// used for the first line.
+func (g *Generator) familyLines(family []string, lines []*form.Line) ([]string, []string) {
+	generated := []string{}
+	endWords := []string{}
+	for _, member := range family {
+		tokens := g.endingLine(member, lines[len(generated)])
+		if tokens == nil {
+			if len(endWords) == 0 {
+				// no point trying the rest of the family without the first word
+				return nil, nil
+			}
+			continue
+		}
+
+		generated = append(generated, strings.Join(tokens, " "))
+		endWords = append(endWords, member)
+		if len(generated) == len(lines) {
+			return generated, endWords
+		}
+	}
+
+	return nil, nil
+}
+
+// rhymeFamilies returns, for each pronunciation of word, word followed by the
+// other corpus words that rhyme with it at at least _strength_ and can end a
+// line, in random order. A pronunciation that rhymes with any of the words in
+// used is left out, so that each family has a rhyme sound of its own.
+func (g *Generator) rhymeFamilies(word string, strength int, used map[string]bool) [][]string {
+	families := [][]string{}
+	for _, pronunciation := range g.rhymer.Pronunciations(word) {
+		rhymes := g.rhymer.Rhymes(word, pronunciation, strength)
+		if rhymesWithAny(rhymes, used) {
+			continue
+		}
+
+		families = append(families, g.family(word, rhymes))
+	}
+
+	return families
+}
+
+// slantFamilies is like rhymeFamilies, but with the words that rhyme with word
+// at least a little, scoring at least the generator's slant score
+func (g *Generator) slantFamilies(word string, used map[string]bool) [][]string {
+	families := [][]string{}
+	for _, pronunciation := range g.rhymer.Pronunciations(word) {
+		rhymes := g.rhymer.SlantRhymes(word, pronunciation, g.slant)
+		if rhymesWithAny(rhymes, used) {
+			continue
+		}
+
+		families = append(families, g.family(word, rhymes))
+	}
+
+	return families
+}
+
+// family returns word followed by the rhymes that can end a line, in random
+// order
+func (g *Generator) family(word string, rhymes []*rhymes.Rhyme) []string {
+	family := []string{word}
+	seen := map[string]bool{word: true}
+	for _, i := range rand.Perm(len(rhymes)) {
+		rhyme := rhymes[i].Word
+		if seen[rhyme] || len(g.endings[rhyme]) == 0 {
+			continue
+		}
+
+		seen[rhyme] = true
+		family = append(family, rhyme)
+	}
+
+	return family
+}
+
+func rhymesWithAny(candidates []*rhymes.Rhyme, words map[string]bool) bool {
+	for _, rhyme := range candidates {
+		if words[rhyme.Word] {
+			return true
+		}
+	}
+
+	return false
+}
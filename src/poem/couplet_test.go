@@ -0,0 +1,94 @@
+package poem
+
+import (
+	"testing"
+
+	"github.com/verkestk/goetry/src/rhymes"
+)
+
+// rhymesWith checks whether any pronunciation of word1 rhymes with word2
+func rhymesWith(generator *Generator, word1, word2 string, strength int) bool {
+	for _, pronunciation := range generator.rhymer.Pronunciations(word1) {
+		for _, rhyme := range generator.rhymer.Rhymes(word1, pronunciation, strength) {
+			if rhyme.Word == word2 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func Test_Couplets(t *testing.T) {
+	generator, rhmr := loadTestGenerator(t)
+
+	couplets, err := generator.Couplets(3, 1, 8)
+	if err != nil {
+		t.Fatalf("Error generating couplets: %v", err)
+	}
+
+	if len(couplets.Stanzas) != 3 {
+		t.Fatalf("expected 3 couplets, got:\n%s", couplets)
+	}
+
+	endWords := map[string]bool{}
+	for _, couplet := range couplets.Stanzas {
+		if len(couplet) != 2 {
+			t.Fatalf("expected 2 lines per couplet, got:\n%s", couplets)
+		}
+
+		for _, line := range couplet {
+			if !hasCount(rhmr.LineSyllables(line), 8) {
+				t.Errorf("expected 8 syllables, got %v: \"%s\"", rhmr.LineSyllables(line), line)
+			}
+
+			word := endWord(line)
+			if endWords[word] {
+				t.Errorf("end word \"%s\" used more than once", word)
+			}
+			endWords[word] = true
+		}
+
+		if !rhymesWith(generator, endWord(couplet[0]), endWord(couplet[1]), 1) {
+			t.Errorf("expected couplet to rhyme:\n%s\n%s", couplet[0], couplet[1])
+		}
+	}
+}
+
+func Test_Couplets_impossible(t *testing.T) {
+	generator, _ := loadTestGenerator(t)
+
+	couplets, err := generator.Couplets(1, 10, 8)
+	if err == nil {
+		t.Errorf("expected error, got couplets:\n%s", couplets)
+	}
+}
+
+func Test_Couplets_slant(t *testing.T) {
+	generator, rhmr := loadTestGenerator(t)
+	generator.AllowSlantRhymes(0.7)
+
+	// no perfect rhymes are strong enough, so every couplet falls back on near rhymes
+	couplets, err := generator.Couplets(2, 10, 8)
+	if err != nil {
+		t.Fatalf("Error generating couplets: %v", err)
+	}
+
+	for _, couplet := range couplets.Stanzas {
+		word1 := endWord(couplet[0])
+		word2 := endWord(couplet[1])
+
+		best := 0.0
+		for _, pronunciation1 := range rhmr.Pronunciations(word1) {
+			for _, pronunciation2 := range rhmr.Pronunciations(word2) {
+				score, _ := rhymes.RhymeScore(pronunciation1, pronunciation2)
+				if score > best {
+					best = score
+				}
+			}
+		}
+		if best < 0.7 {
+			t.Errorf("expected couplet to slant rhyme at 0.7, got %f:\n%s\n%s", best, couplet[0], couplet[1])
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package poem
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/verkestk/goetry/src/form"
+	"github.com/verkestk/goetry/src/meter"
+)
+
+// Form generates a poem following a form definition. The rhyming lines are
+// generated first, a rhyme group at a time, each line generated backward from
+// its end word. Refrains are generated once and repeated. Then the lines that
+// don't rhyme are filled in, each continuing the markov chain from the line
+// before it.
+func (g *Generator) Form(f *form.Form) (*Poem, error) {
+	stanzas, err := f.Lines()
+	if err != nil {
+		return nil, fmt.Errorf("invalid form: %w", err)
+	}
+
+	strength := f.Strength
+	if strength == 0 {
+		strength = 1
+	}
+
+	// gather the distinct lines of each rhyme group, in order of appearance - a
+	// refrain only needs generating once
+	groupNames := []string{}
+	groups := map[string][]*form.Line{}
+	refrains := map[string]*form.Line{}
+	for _, stanza := range stanzas {
+		for _, line := range stanza {
+			if line.Rhyme == "" {
+				continue
+			}
+			if line.Refrain != "" {
+				if refrains[line.Refrain] != nil {
+					continue
+				}
+				refrains[line.Refrain] = line
+			}
+			if groups[line.Rhyme] == nil {
+				groupNames = append(groupNames, line.Rhyme)
+			}
+			groups[line.Rhyme] = append(groups[line.Rhyme], line)
+		}
+	}
+
+	texts := map[*form.Line]string{}
+	used := map[string]bool{}
+	for _, name := range groupNames {
+		lines, err := g.rhymingLines(groups[name], strength, used)
+		if err != nil {
+			return nil, fmt.Errorf("error generating rhyme group %s: %w", name, err)
+		}
+
+		for i, line := range groups[name] {
+			texts[line] = lines[i]
+		}
+	}
+
+	poem := &Poem{}
+	var previous []string
+	for _, stanza := range stanzas {
+		lines := []string{}
+		scansions := []*meter.Scansion{}
+		for _, line := range stanza {
+			if line.Refrain != "" {
+				texts[line] = texts[refrains[line.Refrain]]
+			}
+
+			text, ok := texts[line]
+			if !ok {
+				tokens := g.forwardLine(line, previous)
+				if tokens == nil {
+					return nil, fmt.Errorf("unable to generate line %d of stanza %d from the corpus in %d attempts", len(lines)+1, len(poem.Stanzas)+1, g.attempts)
+				}
+				text = strings.Join(tokens, " ")
+			}
+
+			var scansion *meter.Scansion
+			if line.Foot != nil {
+				scansion = scanLine(g.rhymer.LineStresses(text), line)
+			}
+
+			lines = append(lines, text)
+			scansions = append(scansions, scansion)
+			previous = strings.Fields(text)
+		}
+		poem.Stanzas = append(poem.Stanzas, lines)
+		poem.Scansions = append(poem.Scansions, scansions)
+	}
+
+	return poem, nil
+}
@@ -0,0 +1,48 @@
+package poem
+
+import (
+	"testing"
+
+	"github.com/verkestk/goetry/src/form"
+)
+
+func Test_Generator_Form(t *testing.T) {
+	generator, rhmr := loadTestGenerator(t)
+
+	f := &form.Form{Name: "test", Rhyme: "A1bA A1b -", Syllables: []int{6}}
+	p, err := generator.Form(f)
+	if err != nil {
+		t.Fatalf("Error generating poem: %v", err)
+	}
+
+	if len(p.Stanzas) != 3 || len(p.Stanzas[0]) != 3 || len(p.Stanzas[1]) != 2 || len(p.Stanzas[2]) != 1 {
+		t.Fatalf("expected stanzas of 3, 2 and 1 lines, got:\n%s", p)
+	}
+
+	for _, stanza := range p.Stanzas {
+		for _, line := range stanza {
+			if !hasCount(rhmr.LineSyllables(line), 6) {
+				t.Errorf("expected 6 syllables, got %v: \"%s\"", rhmr.LineSyllables(line), line)
+			}
+		}
+	}
+
+	if p.Stanzas[0][0] != p.Stanzas[1][0] {
+		t.Errorf("expected refrain to repeat, got:\n%s", p)
+	}
+	if !rhymesWith(generator, endWord(p.Stanzas[0][0]), endWord(p.Stanzas[0][2]), 1) {
+		t.Errorf("expected A lines to rhyme, got:\n%s", p)
+	}
+	if !rhymesWith(generator, endWord(p.Stanzas[0][1]), endWord(p.Stanzas[1][1]), 1) {
+		t.Errorf("expected B lines to rhyme, got:\n%s", p)
+	}
+}
+
+func Test_Generator_Form_invalid(t *testing.T) {
+	generator, _ := loadTestGenerator(t)
+
+	p, err := generator.Form(&form.Form{Stanzas: []int{3}})
+	if err == nil {
+		t.Errorf("expected error, got poem:\n%s", p)
+	}
+}
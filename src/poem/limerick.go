@@ -0,0 +1,29 @@
+package poem
+
+import (
+	"github.com/verkestk/goetry/src/form"
+)
+
+// Limerick generates a limerick - five lines of anapestic meter rhyming AABBA
+// at at least _strength_. Lines 1, 2 and 5 are trimeter and lines 3 and 4 are
+// dimeter, and the two rhyme sounds don't rhyme with each other. Each line can
+// have up to _tolerance_ syllables that conflict with the meter. A feminine
+// ending and a short first foot (an iamb in place of the first anapest) don't
+// count against the tolerance.
+func (g *Generator) Limerick(strength, tolerance int) (*Poem, error) {
+	return g.Form(&form.Form{
+		Name:  "limerick",
+		Rhyme: "AABBA",
+		Meter: []string{
+			"anapestic trimeter",
+			"anapestic trimeter",
+			"anapestic dimeter",
+			"anapestic dimeter",
+			"anapestic trimeter",
+		},
+		Tolerance:       tolerance,
+		FeminineEndings: true,
+		ShortFirstFoot:  true,
+		Strength:        strength,
+	})
+}
@@ -0,0 +1,42 @@
+package poem
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Limerick(t *testing.T) {
+	generator, _ := loadTestGenerator(t)
+
+	limerick, err := generator.Limerick(1, 1)
+	if err != nil {
+		t.Fatalf("Error generating limerick: %v", err)
+	}
+
+	if len(limerick.Stanzas) != 1 || len(limerick.Stanzas[0]) != 5 {
+		t.Fatalf("expected 1 stanza of 5 lines, got:\n%s", limerick)
+	}
+
+	lines := limerick.Stanzas[0]
+	for i, feet := range []int{3, 3, 2, 2, 3} {
+		scansion := limerick.Scansions[0][i]
+		if scansion == nil || scansion.Foot.Name != "anapest" || scansion.Feet != feet || scansion.Deviation > 1 {
+			t.Errorf("expected anapestic line of %d feet with deviation at most 1, got %+v: \"%s\"", feet, scansion, lines[i])
+		}
+	}
+
+	if !rhymesWith(generator, endWord(lines[0]), endWord(lines[1]), 1) || !rhymesWith(generator, endWord(lines[0]), endWord(lines[4]), 1) {
+		t.Errorf("expected lines 1, 2 and 5 to rhyme, got:\n%s", limerick)
+	}
+	if !rhymesWith(generator, endWord(lines[2]), endWord(lines[3]), 1) {
+		t.Errorf("expected lines 3 and 4 to rhyme, got:\n%s", limerick)
+	}
+	if rhymesWith(generator, endWord(lines[0]), endWord(lines[2]), 1) {
+		t.Errorf("expected two distinct rhyme sounds, got:\n%s", limerick)
+	}
+
+	annotated := limerick.StringWithScansion()
+	if !strings.Contains(annotated, "anapestic trimeter") || !strings.Contains(annotated, "anapestic dimeter") {
+		t.Errorf("expected scansion in annotated limerick, got:\n%s", annotated)
+	}
+}
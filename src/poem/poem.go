@@ -0,0 +1,244 @@
+package poem
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/verkestk/markovokram"
+
+	"github.com/verkestk/goetry/src/corpus"
+	"github.com/verkestk/goetry/src/form"
+	"github.com/verkestk/goetry/src/meter"
+	"github.com/verkestk/goetry/src/rhymes"
+	"github.com/verkestk/goetry/src/util/markov"
+)
+
+// Poem is a generated poem - a list of stanzas, each a list of lines
+type Poem struct {
+	Stanzas [][]string
+
+	// how each line of each stanza fits its meter, nil for lines without one
+	Scansions [][]*meter.Scansion
+}
+
+// String formats the poem with one line per line and a blank line between
+// stanzas
+func (p *Poem) String() string {
+	stanzas := []string{}
+	for _, stanza := range p.Stanzas {
+		stanzas = append(stanzas, strings.Join(stanza, "\n"))
+	}
+
+	return strings.Join(stanzas, "\n\n")
+}
+
+// StringWithScansion formats the poem like String, with the stress of each
+// word and the fit to the meter under each line that has a meter
+func (p *Poem) StringWithScansion() string {
+	stanzas := []string{}
+	for i, stanza := range p.Stanzas {
+		lines := []string{}
+		for j, line := range stanza {
+			lines = append(lines, line)
+			if i >= len(p.Scansions) || j >= len(p.Scansions[i]) || p.Scansions[i][j] == nil {
+				continue
+			}
+
+			scansion := p.Scansions[i][j]
+			marks := []string{}
+			for k, word := range rhymes.Tokenize(line) {
+				if k < len(scansion.Stresses) {
+					marks = append(marks, fmt.Sprintf("%s(%s)", strings.ToLower(word), scansion.Stresses[k]))
+				}
+			}
+			lines = append(lines, fmt.Sprintf("    %s", strings.Join(marks, " ")))
+			lines = append(lines, fmt.Sprintf("    %s, deviation %d", scansion, scansion.Deviation))
+		}
+		stanzas = append(stanzas, strings.Join(lines, "\n"))
+	}
+
+	return strings.Join(stanzas, "\n\n")
+}
+
+// Generator generates poems from a markov chain built from a corpus, using a
+// Rhymer for the pronunciation of the corpus words.
+type Generator struct {
+	chain  *markovokram.Chain
+	rhymer *rhymes.Rhymer
+
+	// for each word in the corpus, the sequences of corpus tokens that end with
+	// it, long enough to walk the chain backward from
+	endings map[string][][]string
+
+	// for each word in the corpus, the number of times it follows a determiner
+	// like "the" or "my" - a sign that it's a noun
+	nouns map[string]int
+
+	// the maximum number of words to draw from the chain for each line
+	attempts int
+
+	// the minimum score of a slant rhyme to fall back on when there aren't
+	// enough perfect rhymes, or 0 for perfect rhymes only
+	slant float64
+}
+
+// words that come before a noun
+var determiners = map[string]bool{
+	"a": true, "an": true, "the": true, "this": true, "that": true, "these": true,
+	"those": true, "my": true, "your": true, "his": true, "her": true, "its": true,
+	"our": true, "their": true, "every": true, "each": true, "no": true,
+	"some": true, "any": true,
+}
+
+// NewGenerator builds a markov chain from the lines of a corpus, ready to
+// generate poems. Each line of a poem gives up after drawing _attempts_ words
+// from the chain.
+func NewGenerator(cor *corpus.Corpus, rhymer *rhymes.Rhymer, prefixLength int, attempts int) *Generator {
+	g := &Generator{
+		chain:    markovokram.NewChain(prefixLength),
+		rhymer:   rhymer,
+		endings:  map[string][][]string{},
+		nouns:    map[string]int{},
+		attempts: attempts,
+	}
+
+	for _, line := range cor.Lines {
+		tokens := strings.Fields(line)
+		g.chain.Build(tokens)
+
+		for i := prefixLength - 1; i < len(tokens); i++ {
+			word := endWord(tokens[i])
+			if word == "" {
+				continue
+			}
+			g.endings[word] = append(g.endings[word], tokens[i-prefixLength+1:i+1])
+		}
+
+		for i := 1; i < len(tokens); i++ {
+			word := endWord(tokens[i])
+			if determiners[endWord(tokens[i-1])] && word != "" && !determiners[word] {
+				g.nouns[word]++
+			}
+		}
+	}
+
+	return g
+}
+
+// AllowSlantRhymes lets rhyming lines end in near rhymes - assonant, consonant
+// and slant rhymes scoring at least _minScore_ (see rhymes.RhymeScore) - when
+// there aren't enough perfect rhymes. A _minScore_ of 0 allows perfect rhymes
+// only.
+func (g *Generator) AllowSlantRhymes(minScore float64) {
+	g.slant = minScore
+}
+
+// SyllableLines generates one stanza with a line for each of the syllable
+// counts. Each line continues the markov chain from the line before it when it
+// can, and starts fresh when it can't.
+func (g *Generator) SyllableLines(syllables []int) (*Poem, error) {
+	lines := []string{}
+	var previous []string
+	for _, count := range syllables {
+		tokens := g.forwardLine(&form.Line{Syllables: count}, previous)
+		if tokens == nil {
+			return nil, fmt.Errorf("unable to generate a line of %d syllables from the corpus in %d attempts", count, g.attempts)
+		}
+
+		lines = append(lines, strings.Join(tokens, " "))
+		previous = tokens
+	}
+
+	return &Poem{Stanzas: [][]string{lines}}, nil
+}
+
+// Haiku generates a three line poem of 5, 7 and 5 syllables
+func (g *Generator) Haiku() (*Poem, error) {
+	return g.SyllableLines([]int{5, 7, 5})
+}
+
+// forwardLine generates a line that satisfies line, continuing the markov chain
+// from the words in previous when it can, and starting fresh when it can't.
+// Returns nil if no line could be generated.
+func (g *Generator) forwardLine(line *form.Line, previous []string) []string {
+	lengths := line.Lengths()
+	for _, i := range rand.Perm(len(lengths)) {
+		tokens := markov.GenerateSyllables(g.chain, previous, lengths[i], g.rhymer.LineSyllables, g.meterFilter(line), g.attempts)
+		if tokens == nil && len(previous) > 0 {
+			tokens = markov.GenerateSyllables(g.chain, nil, lengths[i], g.rhymer.LineSyllables, g.meterFilter(line), g.attempts)
+		}
+		if tokens != nil {
+			return tokens
+		}
+	}
+
+	return nil
+}
+
+// endingLine generates a line that satisfies line and ends with word, walking
+// the chain backward from one of the places word appears in the corpus.
+// Returns nil if no line could be generated.
+func (g *Generator) endingLine(word string, line *form.Line) []string {
+	endings := g.endings[strings.ToLower(word)]
+	lengths := line.Lengths()
+	for _, i := range rand.Perm(len(endings)) {
+		for _, j := range rand.Perm(len(lengths)) {
+			tokens := markov.GenerateSyllablesBackward(g.chain, endings[i], lengths[j], g.rhymer.LineSyllables, g.meterFilter(line), g.attempts)
+			if tokens != nil {
+				return tokens
+			}
+		}
+	}
+
+	return nil
+}
+
+// meterFilter accepts lines that fit the meter of line within its tolerance.
+// Returns nil for lines without a meter.
+func (g *Generator) meterFilter(line *form.Line) markov.LineFilter {
+	if line.Foot == nil {
+		return nil
+	}
+
+	return func(tokens []string) bool {
+		wordStresses := g.rhymer.LineStresses(strings.Join(tokens, " "))
+		if wordStresses == nil {
+			return false
+		}
+
+		return scanLine(wordStresses, line).Deviation <= line.Tolerance
+	}
+}
+
+// scanLine scores a line of words against the meter of line - the exact meter
+// if the line has a length in feet, and just the foot if it doesn't
+func scanLine(wordStresses [][]string, line *form.Line) *meter.Scansion {
+	if line.Feet > 0 {
+		return meter.Fit(wordStresses, *line.Foot, line.Feet, line.Variations)
+	}
+
+	return meter.Scan(wordStresses, *line.Foot)
+}
+
+// endWords returns the words that can end a line, in sorted order
+func (g *Generator) endWords() []string {
+	words := []string{}
+	for word := range g.endings {
+		words = append(words, word)
+	}
+
+	sort.Strings(words)
+	return words
+}
+
+// endWord is the word a token ends with, lowercased for lookup in a Rhymer
+func endWord(token string) string {
+	words := rhymes.Tokenize(token)
+	if len(words) == 0 {
+		return ""
+	}
+
+	return strings.ToLower(words[len(words)-1])
+}
@@ -0,0 +1,76 @@
+package poem
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/verkestk/goetry/src/corpus"
+	"github.com/verkestk/goetry/src/rhymes"
+)
+
+func loadTestGenerator(t *testing.T) (*Generator, *rhymes.Rhymer) {
+	cor, _, err := corpus.Load("../corpus/test_corpus.json", "")
+	if err != nil {
+		t.Fatalf("Error loading corpus: %v", err)
+	}
+
+	rhmr, err := rhymes.Load("../rhymes/test_dictionary.txt", cor)
+	if err != nil {
+		t.Fatalf("Error loading pronunciation dictionary: %v", err)
+	}
+
+	rand.Seed(1)
+	return NewGenerator(cor, rhmr, 2, 10000), rhmr
+}
+
+func hasCount(counts []int, count int) bool {
+	for _, c := range counts {
+		if c == count {
+			return true
+		}
+	}
+
+	return false
+}
+
+func Test_Haiku(t *testing.T) {
+	generator, rhmr := loadTestGenerator(t)
+
+	for i := 0; i < 10; i++ {
+		haiku, err := generator.Haiku()
+		if err != nil {
+			t.Fatalf("Error generating haiku: %v", err)
+		}
+
+		if len(haiku.Stanzas) != 1 || len(haiku.Stanzas[0]) != 3 {
+			t.Fatalf("expected 1 stanza of 3 lines, got:\n%s", haiku)
+		}
+
+		for index, expected := range []int{5, 7, 5} {
+			line := haiku.Stanzas[0][index]
+			if !hasCount(rhmr.LineSyllables(line), expected) {
+				t.Errorf("expected line %d to have %d syllables, got %v: \"%s\"", index, expected, rhmr.LineSyllables(line), line)
+			}
+		}
+	}
+}
+
+func Test_SyllableLines_impossible(t *testing.T) {
+	_, rhmr := loadTestGenerator(t)
+
+	// "call me." is the only line, so 3 syllables can never be generated
+	generator := NewGenerator(&corpus.Corpus{Lines: []string{"Call me."}}, rhmr, 2, 1000)
+
+	poem, err := generator.SyllableLines([]int{3})
+	if err == nil {
+		t.Errorf("expected error, got poem:\n%s", poem)
+	}
+}
+
+func Test_Poem_String(t *testing.T) {
+	poem := &Poem{Stanzas: [][]string{[]string{"a", "b"}, []string{"c"}}}
+	expected := "a\nb\n\nc"
+	if poem.String() != expected {
+		t.Errorf("expected %q, got %q", expected, poem.String())
+	}
+}
@@ -0,0 +1,155 @@
+package poem
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/verkestk/goetry/src/form"
+	"github.com/verkestk/goetry/src/util/markov"
+)
+
+// the number of end words in a sestina, and lines in each of its stanzas
+const sestinaWords = 6
+
+// retrogradatio cruciata - the end word of each line of a stanza is the end word
+// of this line of the stanza before
+var sestinaRotation = []int{5, 0, 4, 1, 3, 2}
+
+// the end words (as positions in the first stanza) in the middle and at the end
+// of each line of the envoi
+var sestinaEnvoi = [][]int{{1, 4}, {3, 2}, {5, 0}}
+
+// Sestina generates a sestina - six stanzas of six lines of _syllables_
+// syllables, then a three line envoi. The same six end words are used in every
+// stanza, rotated from one stanza to the next: the end words of the last and
+// first lines of a stanza end the first two lines of the next, then the fifth
+// and second, then the fourth and third. Each line of the envoi has two of the
+// end words, one in the middle and one at the end.
+//
+// If _words_ is empty, the end words are chosen from the nouns used most often
+// in the corpus. Otherwise it must have six words.
+func (g *Generator) Sestina(syllables int, words []string) (*Poem, error) {
+	if len(words) != 0 && len(words) != sestinaWords {
+		return nil, fmt.Errorf("a sestina needs %d end words, got %d", sestinaWords, len(words))
+	}
+
+	line := &form.Line{Syllables: syllables}
+	first, words, err := g.sestinaFirstStanza(line, words)
+	if err != nil {
+		return nil, err
+	}
+
+	poem := &Poem{Stanzas: [][]string{first}}
+	previous := strings.Fields(first[len(first)-1])
+	endWords := words
+	for len(poem.Stanzas) < sestinaWords {
+		rotated := []string{}
+		for _, i := range sestinaRotation {
+			rotated = append(rotated, endWords[i])
+		}
+		endWords = rotated
+
+		stanza := []string{}
+		for _, word := range endWords {
+			tokens := g.lineEndingWith(word, line, previous)
+			if tokens == nil {
+				return nil, fmt.Errorf("unable to generate line %d of stanza %d ending in \"%s\" in %d attempts", len(stanza)+1, len(poem.Stanzas)+1, word, g.attempts)
+			}
+
+			stanza = append(stanza, strings.Join(tokens, " "))
+			previous = tokens
+		}
+		poem.Stanzas = append(poem.Stanzas, stanza)
+	}
+
+	envoi := []string{}
+	middle := &form.Line{Syllables: syllables / 2}
+	end := &form.Line{Syllables: syllables - syllables/2}
+	for _, pair := range sestinaEnvoi {
+		start := g.lineEndingWith(words[pair[0]], middle, previous)
+		if start == nil {
+			return nil, fmt.Errorf("unable to generate line %d of the envoi with \"%s\" in %d attempts", len(envoi)+1, words[pair[0]], g.attempts)
+		}
+
+		finish := g.lineEndingWith(words[pair[1]], end, start)
+		if finish == nil {
+			return nil, fmt.Errorf("unable to generate line %d of the envoi ending in \"%s\" in %d attempts", len(envoi)+1, words[pair[1]], g.attempts)
+		}
+
+		envoi = append(envoi, strings.Join(append(start, finish...), " "))
+		previous = finish
+	}
+	poem.Stanzas = append(poem.Stanzas, envoi)
+
+	return poem, nil
+}
+
+// sestinaFirstStanza generates the first stanza of a sestina, ending with each
+// of words in turn. If words is empty, it chooses the end words as it goes,
+// working down the corpus nouns from the most used until six of them can end a
+// line. Returns the stanza and its end words.
+func (g *Generator) sestinaFirstStanza(line *form.Line, words []string) ([]string, []string, error) {
+	candidates := words
+	if len(candidates) == 0 {
+		candidates = g.frequentNouns()
+	}
+
+	stanza := []string{}
+	chosen := []string{}
+	for _, word := range candidates {
+		word = strings.ToLower(word)
+		tokens := g.endingLine(word, line)
+		if tokens == nil {
+			if len(words) > 0 {
+				return nil, nil, fmt.Errorf("unable to generate a line ending in \"%s\" from the corpus", word)
+			}
+			continue
+		}
+
+		stanza = append(stanza, strings.Join(tokens, " "))
+		chosen = append(chosen, word)
+		if len(chosen) == sestinaWords {
+			return stanza, chosen, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("unable to find %d nouns in the corpus that can end a line", sestinaWords)
+}
+
+// frequentNouns returns the words that follow a determiner in the corpus, most
+// used first. Words used equally often are shuffled, so that a small corpus
+// doesn't always produce the same end words.
+func (g *Generator) frequentNouns() []string {
+	nouns := []string{}
+	for noun := range g.nouns {
+		nouns = append(nouns, noun)
+	}
+
+	sort.Strings(nouns)
+	rand.Shuffle(len(nouns), func(i, j int) { nouns[i], nouns[j] = nouns[j], nouns[i] })
+	sort.SliceStable(nouns, func(i, j int) bool { return g.nouns[nouns[i]] > g.nouns[nouns[j]] })
+
+	return nouns
+}
+
+// lineEndingWith generates a line that satisfies line and ends with word. It
+// first tries continuing the markov chain forward from the words in previous,
+// and then falls back to walking the chain backward from word. Returns nil if
+// no line could be generated.
+func (g *Generator) lineEndingWith(word string, line *form.Line, previous []string) []string {
+	meterFilter := g.meterFilter(line)
+	var filter markov.LineFilter = func(tokens []string) bool {
+		return len(tokens) > 0 && endWord(tokens[len(tokens)-1]) == word && (meterFilter == nil || meterFilter(tokens))
+	}
+
+	for _, length := range line.Lengths() {
+		tokens := markov.GenerateSyllables(g.chain, previous, length, g.rhymer.LineSyllables, filter, g.attempts)
+		if tokens != nil {
+			return tokens
+		}
+	}
+
+	return g.endingLine(word, line)
+}
@@ -0,0 +1,74 @@
+package poem
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Sestina(t *testing.T) {
+	generator, rhymer := loadTestGenerator(t)
+
+	sestina, err := generator.Sestina(10, nil)
+	if err != nil {
+		t.Fatalf("Error generating sestina: %v", err)
+	}
+
+	if len(sestina.Stanzas) != 7 {
+		t.Fatalf("expected 7 stanzas, got:\n%s", sestina)
+	}
+
+	words := []string{}
+	for _, line := range sestina.Stanzas[0] {
+		words = append(words, endWord(line))
+	}
+
+	for i, stanza := range sestina.Stanzas[:6] {
+		if len(stanza) != 6 {
+			t.Fatalf("expected 6 lines in stanza %d, got:\n%s", i+1, sestina)
+		}
+		for j, line := range stanza {
+			if endWord(line) != words[j] {
+				t.Errorf("expected line %d of stanza %d to end in \"%s\": \"%s\"", j+1, i+1, words[j], line)
+			}
+			if !hasCount(rhymer.LineSyllables(line), 10) {
+				t.Errorf("expected 10 syllables: \"%s\"", line)
+			}
+		}
+
+		// retrogradatio cruciata
+		words = []string{words[5], words[0], words[4], words[1], words[3], words[2]}
+	}
+
+	// after six rotations, the end words are back in their first order
+	envoi := sestina.Stanzas[6]
+	if len(envoi) != 3 {
+		t.Fatalf("expected 3 lines in the envoi, got:\n%s", sestina)
+	}
+	for i, pair := range [][]int{{1, 4}, {3, 2}, {5, 0}} {
+		tokens := strings.Fields(envoi[i])
+		found := false
+		for _, token := range tokens[:len(tokens)-1] {
+			found = found || endWord(token) == words[pair[0]]
+		}
+		if !found || endWord(envoi[i]) != words[pair[1]] {
+			t.Errorf("expected line %d of the envoi to have \"%s\" and end in \"%s\": \"%s\"", i+1, words[pair[0]], words[pair[1]], envoi[i])
+		}
+	}
+}
+
+func Test_Sestina_Words(t *testing.T) {
+	generator, _ := loadTestGenerator(t)
+
+	sestina, err := generator.Sestina(10, []string{"sound", "bodyguard", "man", "middle", "role", "long"})
+	if err != nil {
+		t.Fatalf("Error generating sestina: %v", err)
+	}
+	if endWord(sestina.Stanzas[0][0]) != "sound" || endWord(sestina.Stanzas[1][0]) != "long" {
+		t.Errorf("expected the given end words, got:\n%s", sestina)
+	}
+
+	_, err = generator.Sestina(10, []string{"sound"})
+	if err == nil {
+		t.Errorf("expected error generating sestina with too few end words")
+	}
+}
@@ -0,0 +1,21 @@
+package poem
+
+import (
+	"github.com/verkestk/goetry/src/form"
+)
+
+// Sonnet generates a Shakespearean sonnet - three quatrains and a couplet of
+// iambic pentameter, rhyming ABAB CDCD EFEF GG at at least _strength_. Each line
+// can have up to _tolerance_ syllables that conflict with the meter. Feminine
+// endings and an inverted first foot don't count against the tolerance.
+func (g *Generator) Sonnet(strength, tolerance int) (*Poem, error) {
+	return g.Form(&form.Form{
+		Name:             "Shakespearean sonnet",
+		Rhyme:            "ABAB CDCD EFEF GG",
+		Meter:            []string{"iambic pentameter"},
+		Tolerance:        tolerance,
+		FeminineEndings:  true,
+		InitialInversion: true,
+		Strength:         strength,
+	})
+}
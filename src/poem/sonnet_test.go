@@ -0,0 +1,40 @@
+package poem
+
+import (
+	"testing"
+
+	"github.com/verkestk/goetry/src/meter"
+)
+
+func Test_Sonnet(t *testing.T) {
+	generator, rhmr := loadTestGenerator(t)
+
+	sonnet, err := generator.Sonnet(1, 2)
+	if err != nil {
+		t.Fatalf("Error generating sonnet: %v", err)
+	}
+
+	if len(sonnet.Stanzas) != 4 || len(sonnet.Stanzas[0]) != 4 || len(sonnet.Stanzas[1]) != 4 || len(sonnet.Stanzas[2]) != 4 || len(sonnet.Stanzas[3]) != 2 {
+		t.Fatalf("expected three quatrains and a couplet, got:\n%s", sonnet)
+	}
+
+	variations := meter.Variations{FeminineEnding: true, InitialInversion: true}
+	for _, stanza := range sonnet.Stanzas {
+		for _, line := range stanza {
+			scansion := meter.Fit(rhmr.LineStresses(line), meter.Iamb, 5, variations)
+			if scansion.Deviation > 2 {
+				t.Errorf("expected deviation at most 2, got %d: \"%s\"", scansion.Deviation, line)
+			}
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		quatrain := sonnet.Stanzas[i]
+		if !rhymesWith(generator, endWord(quatrain[0]), endWord(quatrain[2]), 1) || !rhymesWith(generator, endWord(quatrain[1]), endWord(quatrain[3]), 1) {
+			t.Errorf("expected quatrain to rhyme ABAB, got:\n%s", sonnet)
+		}
+	}
+	if !rhymesWith(generator, endWord(sonnet.Stanzas[3][0]), endWord(sonnet.Stanzas[3][1]), 1) {
+		t.Errorf("expected couplet to rhyme, got:\n%s", sonnet)
+	}
+}
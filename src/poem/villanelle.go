@@ -0,0 +1,133 @@
+package poem
+
+import (
+	"fmt"
+
+	"github.com/verkestk/goetry/src/form"
+	"github.com/verkestk/goetry/src/meter"
+)
+
+// the villanelle rhyme scheme - A1 and A2 are the refrains
+const villanelleRhyme = "A1bA2 abA1 abA2 abA1 abA2 abA1A2"
+
+// Villanelle generates a villanelle - five tercets and a quatrain of iambic
+// pentameter with only two rhyme sounds, rhyming at at least _strength_. The
+// first and third lines (the refrains A1 and A2) are repeated alternately as
+// the last line of each tercet, and together at the end of the quatrain. Each
+// line can have up to _tolerance_ syllables that conflict with the meter.
+//
+// A rhyme family is only chosen if it has enough words for all of its distinct
+// lines - 7 for the first family, including the refrains, and 6 for the second.
+// The refrains are generated before anything else, from the first family, and
+// then every other line is generated backward from one of the remaining words
+// of its family.
+func (g *Generator) Villanelle(strength, tolerance int) (*Poem, error) {
+	f := &form.Form{
+		Name:             "villanelle",
+		Rhyme:            villanelleRhyme,
+		Meter:            []string{"iambic pentameter"},
+		Tolerance:        tolerance,
+		FeminineEndings:  true,
+		InitialInversion: true,
+		Strength:         strength,
+	}
+
+	stanzas, err := f.Lines()
+	if err != nil {
+		return nil, fmt.Errorf("invalid villanelle: %w", err)
+	}
+
+	plan := planVillanelle(stanzas)
+
+	used := map[string]bool{}
+	aTexts, err := g.rhymingLines(plan.aLines, strength, used)
+	if err != nil {
+		return nil, fmt.Errorf("error generating refrains: %w", err)
+	}
+
+	bTexts, err := g.rhymingLines(plan.bLines, strength, used)
+	if err != nil {
+		return nil, fmt.Errorf("error generating second rhyme: %w", err)
+	}
+
+	poem := &Poem{Stanzas: plan.assemble(aTexts, bTexts)}
+	for i, stanza := range poem.Stanzas {
+		scansions := []*meter.Scansion{}
+		for j, text := range stanza {
+			scansions = append(scansions, scanLine(g.rhymer.LineStresses(text), stanzas[i][j]))
+		}
+		poem.Scansions = append(poem.Scansions, scansions)
+	}
+
+	return poem, nil
+}
+
+// villanellePlan keeps track of which lines of a villanelle need generating,
+// and where each generated line goes
+type villanellePlan struct {
+	stanzas [][]*form.Line
+
+	// the refrain names, in order of first appearance
+	refrains []string
+
+	// the distinct lines of the first rhyme family - the refrains first, then the
+	// rest in order of appearance
+	aLines []*form.Line
+
+	// the lines of the second rhyme family, in order of appearance
+	bLines []*form.Line
+}
+
+func planVillanelle(stanzas [][]*form.Line) *villanellePlan {
+	plan := &villanellePlan{stanzas: stanzas}
+	refrainLines := []*form.Line{}
+	others := []*form.Line{}
+	seen := map[string]bool{}
+	for _, stanza := range stanzas {
+		for _, line := range stanza {
+			if line.Refrain != "" {
+				if !seen[line.Refrain] {
+					seen[line.Refrain] = true
+					plan.refrains = append(plan.refrains, line.Refrain)
+					refrainLines = append(refrainLines, line)
+				}
+			} else if line.Rhyme == "A" {
+				others = append(others, line)
+			} else {
+				plan.bLines = append(plan.bLines, line)
+			}
+		}
+	}
+	plan.aLines = append(refrainLines, others...)
+
+	return plan
+}
+
+// assemble lays out generated lines, in the order of aLines and bLines, into
+// the stanzas of the villanelle, repeating the refrains
+func (p *villanellePlan) assemble(aTexts, bTexts []string) [][]string {
+	refrains := map[string]string{}
+	for i, name := range p.refrains {
+		refrains[name] = aTexts[i]
+	}
+	aTexts = aTexts[len(p.refrains):]
+
+	stanzas := [][]string{}
+	for _, stanza := range p.stanzas {
+		lines := []string{}
+		for _, line := range stanza {
+			var text string
+			if line.Refrain != "" {
+				text = refrains[line.Refrain]
+			} else if line.Rhyme == "A" {
+				text, aTexts = aTexts[0], aTexts[1:]
+			} else {
+				text, bTexts = bTexts[0], bTexts[1:]
+			}
+			lines = append(lines, text)
+		}
+		stanzas = append(stanzas, lines)
+	}
+
+	return stanzas
+}
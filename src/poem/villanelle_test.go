@@ -0,0 +1,133 @@
+package poem
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/verkestk/goetry/src/corpus"
+	"github.com/verkestk/goetry/src/form"
+	"github.com/verkestk/goetry/src/rhymes"
+)
+
+func Test_Villanelle(t *testing.T) {
+	generator, _ := loadTestGenerator(t)
+
+	// the test corpus has no second rhyme family with 6 words
+	_, err := generator.Villanelle(1, 3)
+	if err == nil {
+		t.Errorf("expected error generating villanelle from the test corpus")
+	}
+}
+
+func Test_Villanelle_generated(t *testing.T) {
+	// lines of iambic pentameter ending in 7 words rhyming with "night" and 6
+	// rhyming with "day"
+	cor, _, err := corpus.Load("test_villanelle_corpus.json", "")
+	if err != nil {
+		t.Fatalf("Error loading corpus: %v", err)
+	}
+	rhmr, err := rhymes.Load("test_villanelle_dictionary.txt", cor)
+	if err != nil {
+		t.Fatalf("Error loading pronunciation dictionary: %v", err)
+	}
+
+	rand.Seed(1)
+	generator := NewGenerator(cor, rhmr, 2, 10000)
+	villanelle, err := generator.Villanelle(1, 0)
+	if err != nil {
+		t.Fatalf("Error generating villanelle: %v", err)
+	}
+
+	lines := 0
+	for i, stanza := range villanelle.Stanzas {
+		expected := 3
+		if i == 5 {
+			expected = 4
+		}
+		if len(stanza) != expected {
+			t.Fatalf("expected five tercets and a quatrain, got:\n%s", villanelle)
+		}
+		lines += len(stanza)
+	}
+	if lines != 19 {
+		t.Errorf("expected 19 lines, got %d", lines)
+	}
+
+	// A1 ends the second, fourth and sixth stanzas, and A2 the third, fifth and
+	// sixth
+	a1, a2 := villanelle.Stanzas[0][0], villanelle.Stanzas[0][2]
+	if a1 == a2 {
+		t.Errorf("expected different refrains, got \"%s\" twice", a1)
+	}
+	for _, refrain := range []struct {
+		text      string
+		positions [][2]int
+	}{
+		{a1, [][2]int{{1, 2}, {3, 2}, {5, 2}}},
+		{a2, [][2]int{{2, 2}, {4, 2}, {5, 3}}},
+	} {
+		for _, position := range refrain.positions {
+			if villanelle.Stanzas[position[0]][position[1]] != refrain.text {
+				t.Errorf("expected refrain \"%s\" at stanza %d line %d, got:\n%s", refrain.text, position[0]+1, position[1]+1, villanelle)
+			}
+		}
+	}
+
+	// the second line of every stanza rhymes with B, and the rest with A
+	aWords, bWords := []string{}, []string{}
+	for _, stanza := range villanelle.Stanzas {
+		for j, line := range stanza {
+			if j == 1 {
+				bWords = append(bWords, endWord(line))
+			} else {
+				aWords = append(aWords, endWord(line))
+			}
+		}
+	}
+	for _, family := range [][]string{aWords, bWords} {
+		for _, word := range family[1:] {
+			if word != family[0] && !rhymesWith(generator, family[0], word, 1) {
+				t.Errorf("expected \"%s\" to rhyme with \"%s\", got:\n%s", word, family[0], villanelle)
+			}
+		}
+	}
+	if rhymesWith(generator, aWords[0], bWords[0], 1) {
+		t.Errorf("expected two rhyme sounds, got \"%s\" rhyming with \"%s\"", aWords[0], bWords[0])
+	}
+}
+
+func Test_villanellePlan(t *testing.T) {
+	stanzas, err := (&form.Form{Rhyme: villanelleRhyme, Meter: []string{"iambic pentameter"}}).Lines()
+	if err != nil {
+		t.Fatalf("Error resolving lines: %v", err)
+	}
+
+	plan := planVillanelle(stanzas)
+	if !reflect.DeepEqual([]string{"A1", "A2"}, plan.refrains) {
+		t.Errorf("expected refrains [A1 A2], got %v", plan.refrains)
+	}
+	if len(plan.aLines) != 7 || len(plan.bLines) != 6 {
+		t.Fatalf("expected 7 A lines and 6 B lines, got %d and %d", len(plan.aLines), len(plan.bLines))
+	}
+	if plan.aLines[0].Refrain != "A1" || plan.aLines[1].Refrain != "A2" {
+		t.Errorf("expected refrains to be generated first")
+	}
+
+	aTexts := []string{"A1", "A2", "a1", "a2", "a3", "a4", "a5"}
+	bTexts := []string{"b1", "b2", "b3", "b4", "b5", "b6"}
+	expected := [][]string{
+		[]string{"A1", "b1", "A2"},
+		[]string{"a1", "b2", "A1"},
+		[]string{"a2", "b3", "A2"},
+		[]string{"a3", "b4", "A1"},
+		[]string{"a4", "b5", "A2"},
+		[]string{"a5", "b6", "A1", "A2"},
+	}
+	actual := plan.assemble(aTexts, bTexts)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Logf("expected: %v\n", expected)
+		t.Logf("actual: %v\n", actual)
+		t.Errorf("unexpected villanelle layout")
+	}
+}
@@ -0,0 +1,123 @@
+package rhymes
+
+import (
+	"strings"
+)
+
+// the fewest letters in each part of a closed compound, so that words aren't
+// split into the abbreviations and letter names in the dictionary
+const minCompoundPart = 3
+
+// the most parts a closed compound is split into
+const maxCompoundParts = 3
+
+// the most pronunciations derived for a single word
+const maxDerivedPronunciations = 8
+
+// deriveCompound puts together the pronunciations of a word missing from the
+// pronunciation dictionary from the pronunciations of its parts. A hyphenated
+// word is split at its hyphens, and a word (or part) still missing from the
+// dictionary is split into the fewest dictionary words it's made of, like
+// "bonedigger" into "bone" and "digger". Returns nil if the word can't be split
+// into dictionary words. lookup returns the pronunciations of a dictionary
+// word, or nil for a word that isn't in the dictionary.
+//
+// Like most English compounds, the stress falls on the first part - the
+// primary stress of the other parts becomes secondary stress.
+func deriveCompound(word string, lookup func(string) [][]string) [][]string {
+	known := func(part string) bool {
+		return len(lookup(part)) > 0
+	}
+
+	parts := []string{}
+	for _, piece := range strings.Split(word, "-") {
+		pieceParts := compoundParts([]rune(piece), known, maxCompoundParts)
+		if pieceParts == nil {
+			return nil
+		}
+		parts = append(parts, pieceParts...)
+	}
+	if len(parts) < 2 {
+		return nil
+	}
+
+	pronunciations := [][]string{nil}
+	for i, part := range parts {
+		next := [][]string{}
+		seen := map[string]bool{}
+		for _, start := range pronunciations {
+			for _, pronunciation := range lookup(part) {
+				if i > 0 {
+					pronunciation = secondaryStress(pronunciation)
+				}
+
+				combined := append(append([]string{}, start...), pronunciation...)
+				key := strings.Join(combined, " ")
+				if !seen[key] && len(next) < maxDerivedPronunciations {
+					seen[key] = true
+					next = append(next, combined)
+				}
+			}
+		}
+		pronunciations = next
+	}
+
+	return pronunciations
+}
+
+// compoundParts splits a word into at most maxParts known words, with each part
+// at least minCompoundPart letters long. A known word is its own only part. Of
+// the ways to split the word, it prefers the fewest parts, and then the longest
+// shortest part. Returns nil if the word can't be split.
+func compoundParts(word []rune, known func(string) bool, maxParts int) []string {
+	if known(string(word)) {
+		return []string{string(word)}
+	}
+	if maxParts < 2 {
+		return nil
+	}
+
+	var best []string
+	for i := minCompoundPart; i <= len(word)-minCompoundPart; i++ {
+		if !known(string(word[:i])) {
+			continue
+		}
+
+		rest := compoundParts(word[i:], known, maxParts-1)
+		if rest == nil {
+			continue
+		}
+
+		parts := append([]string{string(word[:i])}, rest...)
+		if best == nil || len(parts) < len(best) || (len(parts) == len(best) && shortestPart(parts) > shortestPart(best)) {
+			best = parts
+		}
+	}
+
+	return best
+}
+
+func shortestPart(parts []string) int {
+	shortest := -1
+	for _, part := range parts {
+		if shortest == -1 || len(part) < shortest {
+			shortest = len(part)
+		}
+	}
+
+	return shortest
+}
+
+// secondaryStress returns a copy of a pronunciation with its primary stress
+// made secondary
+func secondaryStress(pronunciation []string) []string {
+	stressed := []string{}
+	for _, phoneme := range pronunciation {
+		if isVowelPhoneme(phoneme) && strings.HasSuffix(phoneme, "1") {
+			phoneme = strings.TrimSuffix(phoneme, "1") + "2"
+		}
+		stressed = append(stressed, phoneme)
+	}
+
+	return stressed
+}
@@ -0,0 +1,115 @@
+package rhymes
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/verkestk/goetry/src/corpus"
+)
+
+func Test_deriveCompound(t *testing.T) {
+	pronunciationMap := map[string][][]string{
+		"bone":    [][]string{[]string{"B", "OW1", "N"}},
+		"digger":  [][]string{[]string{"D", "IH1", "G", "ER0"}},
+		"bon":     [][]string{[]string{"B", "AA1", "N"}},
+		"bat":     [][]string{[]string{"B", "AE1", "T"}},
+		"faced":   [][]string{[]string{"F", "EY1", "S", "T"}},
+		"the":     [][]string{[]string{"DH", "AH0"}, []string{"DH", "IY0"}},
+		"sand":    [][]string{[]string{"S", "AE1", "N", "D"}},
+		"box":     [][]string{[]string{"B", "AA1", "K", "S"}},
+		"sandbox": [][]string{[]string{"S", "AE1", "N", "D", "B", "AA2", "K", "S"}},
+		"poly":    [][]string{[]string{"P", "AA1", "L", "IY0"}},
+		"ox":      [][]string{[]string{"AA1", "K", "S"}},
+	}
+	lookup := func(word string) [][]string {
+		return pronunciationMap[word]
+	}
+
+	expected := map[string][][]string{
+		"bonedigger":   [][]string{[]string{"B", "OW1", "N", "D", "IH2", "G", "ER0"}},
+		"bat-faced":    [][]string{[]string{"B", "AE1", "T", "F", "EY2", "S", "T"}},
+		"the-bat":      [][]string{[]string{"DH", "AH0", "B", "AE2", "T"}, []string{"DH", "IY0", "B", "AE2", "T"}},
+		"sandboxbone":  [][]string{[]string{"S", "AE1", "N", "D", "B", "AA2", "K", "S", "B", "OW2", "N"}},
+		"roly-poly":    nil,
+		"bonediggerxy": nil,
+		"box":          nil,
+		"sandox":       nil,
+	}
+	for word, pronunciations := range expected {
+		actual := deriveCompound(word, lookup)
+		if !reflect.DeepEqual(pronunciations, actual) {
+			t.Errorf("expected %v for \"%s\", got %v", pronunciations, word, actual)
+		}
+	}
+}
+
+func Test_compoundParts(t *testing.T) {
+	known := map[string]bool{"sands": true, "tone": true, "sand": true, "stone": true, "wall": true}
+	isKnown := func(word string) bool {
+		return known[word]
+	}
+
+	// the fewest parts, then the first split
+	expected := []string{"sand", "stone"}
+	actual := compoundParts([]rune("sandstone"), isKnown, 3)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	// the longest shortest part
+	known = map[string]bool{"abc": true, "defghi": true, "abcd": true, "efghi": true, "abcdef": true, "ghi": true}
+	expected = []string{"abcd", "efghi"}
+	actual = compoundParts([]rune("abcdefghi"), isKnown, 3)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+	known = map[string]bool{"sands": true, "tone": true, "sand": true, "stone": true, "wall": true}
+
+	expected = []string{"sand", "stone", "wall"}
+	actual = compoundParts([]rune("sandstonewall"), isKnown, 3)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	if compoundParts([]rune("sandstonewall"), isKnown, 2) != nil {
+		t.Errorf("expected no split into 2 parts")
+	}
+}
+
+func Test_rhymer_DerivedPronunciations(t *testing.T) {
+	cor, _, _ := corpus.Load("../corpus/test_corpus.json", "")
+	rhmr, err := LoadWithOptions("test_dictionary.txt", cor, Options{ExtraDictionaries: []string{"test_compound_dictionary.txt"}})
+	if err != nil {
+		t.Fatalf("Error loading pronunciation dictionary: %v", err)
+	}
+
+	if !reflect.DeepEqual([]string{"beerbelly"}, rhmr.DerivedPronunciations()) {
+		t.Errorf("expected beerbelly to be derived, got %v", rhmr.DerivedPronunciations())
+	}
+	if !reflect.DeepEqual([]string{"bonedigger", "currency", "roly", "scatterings"}, rhmr.UnknownPronunciations()) {
+		t.Errorf("unexpected unknown words %v", rhmr.UnknownPronunciations())
+	}
+
+	expected := [][]string{[]string{"B", "IH1", "R", "B", "EH2", "L", "IY0"}}
+	if !reflect.DeepEqual(expected, rhmr.Pronunciations("Beerbelly")) {
+		t.Errorf("expected %v for \"beerbelly\", got %v", expected, rhmr.Pronunciations("beerbelly"))
+	}
+
+	for _, rhyme := range rhmr.Rhymes("alley", rhmr.Pronunciations("alley")[0], 1) {
+		if rhyme.Derived != (rhyme.Word == "beerbelly") {
+			t.Errorf("unexpected Derived %t for rhyme \"%s\"", rhyme.Derived, rhyme.Word)
+		}
+	}
+
+	// hyphenated words are split into corpus words, and derived when looked up
+	expected = [][]string{[]string{"B", "AE1", "T", "F", "EY2", "S", "T"}}
+	if !reflect.DeepEqual(expected, rhmr.Pronunciations("bat-faced")) {
+		t.Errorf("expected %v for \"bat-faced\", got %v", expected, rhmr.Pronunciations("bat-faced"))
+	}
+	if !reflect.DeepEqual([]int{7}, rhmr.Syllables("photo-opportunity")) {
+		t.Errorf("expected 7 syllables for \"photo-opportunity\", got %v", rhmr.Syllables("photo-opportunity"))
+	}
+	if rhmr.Pronunciations("roly-poly") != nil {
+		t.Errorf("expected no pronunciation for \"roly-poly\"")
+	}
+}
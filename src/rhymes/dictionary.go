@@ -0,0 +1,180 @@
+package rhymes
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// the most errors reported for a dictionary before giving up on it
+const maxDictionaryErrors = 10
+
+// the 39 phonemes of the ARPAbet used by CMUdict. Vowels are marked true, and
+// always end with a stress digit in the dictionary.
+var arpabet = map[string]bool{
+	"AA": true, "AE": true, "AH": true, "AO": true, "AW": true, "AY": true,
+	"EH": true, "ER": true, "EY": true, "IH": true, "IY": true, "OW": true,
+	"OY": true, "UH": true, "UW": true,
+	"B": false, "CH": false, "D": false, "DH": false, "F": false, "G": false,
+	"HH": false, "JH": false, "K": false, "L": false, "M": false, "N": false,
+	"NG": false, "P": false, "R": false, "S": false, "SH": false, "T": false,
+	"TH": false, "V": false, "W": false, "Y": false, "Z": false, "ZH": false,
+}
+
+// dictionaryEntry is a line of a pronunciation dictionary
+type dictionaryEntry struct {
+	// the word, lowercased and without the number of an alternate pronunciation
+	word string
+
+	// the phonemes of the pronunciation, nil for a line deleting all of the
+	// word's pronunciations
+	pronunciation []string
+
+	// whether the line deletes the pronunciation rather than adding it
+	remove bool
+}
+
+// readDictionary reads and checks every line of a pronunciation dictionary in
+// any of the CMUdict layouts - the word and its phonemes separated by spaces or
+// tabs, ";;;" comment lines, "#" comments at the end of a line, uppercase or
+// lowercase words, and Windows line endings. A file that isn't valid UTF-8 is
+// read as Latin-1, like the older CMUdict releases.
+//
+// If deletes is true, a line starting with "-" deletes a pronunciation rather
+// than adding it (see applyExtraDictionary). Returns the entries, and warnings
+// for lines that were skipped. Returns an error listing the lines that can't be
+// read, like a line with a phoneme that isn't in the ARPAbet.
+func readDictionary(dictionaryFilepath string, deletes bool) ([]*dictionaryEntry, []string, error) {
+	contents, err := ioutil.ReadFile(dictionaryFilepath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	contents = bytes.TrimPrefix(contents, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(contents) {
+		contents = latin1ToUTF8(contents)
+	}
+
+	entries := []*dictionaryEntry{}
+	warnings := []string{}
+	problems := []string{}
+	seen := map[string]bool{}
+	for i, line := range strings.Split(string(contents), "\n") {
+		entry, err := parseDictionaryLine(line, deletes)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s:%d: %v", dictionaryFilepath, i+1, err))
+			if len(problems) == maxDictionaryErrors {
+				break
+			}
+			continue
+		}
+		if entry == nil {
+			continue
+		}
+
+		key := fmt.Sprintf("%t %s %s", entry.remove, entry.word, strings.Join(entry.pronunciation, " "))
+		if seen[key] {
+			warnings = append(warnings, fmt.Sprintf("%s:%d: skipping repeated pronunciation of \"%s\"", dictionaryFilepath, i+1, entry.word))
+			continue
+		}
+		seen[key] = true
+
+		entries = append(entries, entry)
+	}
+
+	if len(problems) > 0 {
+		return nil, nil, fmt.Errorf("invalid pronunciation dictionary:\n%s", strings.Join(problems, "\n"))
+	}
+
+	return entries, warnings, nil
+}
+
+// parseDictionaryLine reads a line of a pronunciation dictionary. Returns nil
+// for a blank or comment line.
+func parseDictionaryLine(line string, deletes bool) (*dictionaryEntry, error) {
+	// a "#" starts a comment, unless it starts the word itself (like "#SHARP-SIGN")
+	for i, r := range line {
+		if r == '#' && i > 0 && unicode.IsSpace(rune(line[i-1])) {
+			line = line[:i]
+			break
+		}
+	}
+
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, ";;;") {
+		return nil, nil
+	}
+
+	entry := &dictionaryEntry{}
+	if deletes && strings.HasPrefix(line, deletePrefix) {
+		entry.remove = true
+		line = strings.TrimSpace(line[len(deletePrefix):])
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("missing word")
+	}
+	entry.word = dictionaryWord(fields[0])
+	if len(fields) == 1 {
+		if entry.remove {
+			return entry, nil
+		}
+		return nil, fmt.Errorf("missing pronunciation for \"%s\"", fields[0])
+	}
+
+	for _, phoneme := range fields[1:] {
+		err := checkPhoneme(phoneme)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pronunciation for \"%s\": %w", fields[0], err)
+		}
+	}
+	entry.pronunciation = fields[1:]
+
+	return entry, nil
+}
+
+// checkPhoneme checks that a phoneme is in the ARPAbet, and that it has a stress
+// digit (0, 1 or 2) if and only if it's a vowel
+func checkPhoneme(phoneme string) error {
+	base := strings.TrimRight(phoneme, "012")
+	vowel, ok := arpabet[base]
+	if !ok {
+		return fmt.Errorf("unknown phoneme \"%s\"", phoneme)
+	}
+
+	stress := phoneme[len(base):]
+	if vowel && len(stress) != 1 {
+		return fmt.Errorf("vowel \"%s\" needs a stress of 0, 1 or 2", phoneme)
+	}
+	if !vowel && len(stress) != 0 {
+		return fmt.Errorf("consonant \"%s\" can't be stressed", phoneme)
+	}
+
+	return nil
+}
+
+func latin1ToUTF8(contents []byte) []byte {
+	runes := make([]rune, len(contents))
+	for i, b := range contents {
+		runes[i] = rune(b)
+	}
+
+	return []byte(string(runes))
+}
+
+// dictionaryWord is the word of a dictionary entry, lowercased and without the
+// number of an alternate pronunciation, e.g. "word" for "WORD(1)"
+func dictionaryWord(entry string) string {
+	word := strings.ToLower(entry)
+
+	leftParenIndex := strings.Index(word, "(")
+	if leftParenIndex > 0 {
+		word = word[:leftParenIndex]
+	}
+
+	return word
+}
@@ -0,0 +1,89 @@
+package rhymes
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_parseDictionaryLine(t *testing.T) {
+	valid := map[string]*dictionaryEntry{
+		"WORD  W ER1 D":                    &dictionaryEntry{word: "word", pronunciation: []string{"W", "ER1", "D"}},
+		"WORD(1)  W ER1 D":                 &dictionaryEntry{word: "word", pronunciation: []string{"W", "ER1", "D"}},
+		"word W ER1 D":                     &dictionaryEntry{word: "word", pronunciation: []string{"W", "ER1", "D"}},
+		"word(2)\tW ER1 D\r":               &dictionaryEntry{word: "word", pronunciation: []string{"W", "ER1", "D"}},
+		"word W ER1 D # a comment":         &dictionaryEntry{word: "word", pronunciation: []string{"W", "ER1", "D"}},
+		"#SHARP-SIGN  SH AA1 R P S AY1 N":  &dictionaryEntry{word: "#sharp-sign", pronunciation: []string{"SH", "AA1", "R", "P", "S", "AY1", "N"}},
+		";SEMI-COLON  S EH1 M IY0 K OW1 L": &dictionaryEntry{word: ";semi-colon", pronunciation: []string{"S", "EH1", "M", "IY0", "K", "OW1", "L"}},
+		";;; a comment":                    nil,
+		"   ":                              nil,
+		"-WORD  W ER1 D":                   &dictionaryEntry{word: "-word", pronunciation: []string{"W", "ER1", "D"}},
+	}
+	for line, expected := range valid {
+		actual, err := parseDictionaryLine(line, false)
+		if err != nil {
+			t.Errorf("unexpected error parsing \"%s\": %v", line, err)
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("expected %+v for \"%s\", got %+v", expected, line, actual)
+		}
+	}
+
+	deletes := map[string]*dictionaryEntry{
+		"-WORD  W ER1 D": &dictionaryEntry{word: "word", pronunciation: []string{"W", "ER1", "D"}, remove: true},
+		"-WORD":          &dictionaryEntry{word: "word", remove: true},
+		"WORD  W ER1 D":  &dictionaryEntry{word: "word", pronunciation: []string{"W", "ER1", "D"}},
+	}
+	for line, expected := range deletes {
+		actual, err := parseDictionaryLine(line, true)
+		if err != nil {
+			t.Errorf("unexpected error parsing \"%s\": %v", line, err)
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("expected %+v for \"%s\", got %+v", expected, line, actual)
+		}
+	}
+
+	invalid := []string{"INVALID", "WORD  PHONEME1 PHONEME2", "WORD  W ER D", "WORD  W1 ER1 D", "WORD  W ER12 D", "-WORD"}
+	for _, line := range invalid {
+		_, err := parseDictionaryLine(line, false)
+		if err == nil {
+			t.Errorf("expected error parsing \"%s\"", line)
+		}
+	}
+}
+
+func Test_readDictionary(t *testing.T) {
+	entries, warnings, err := readDictionary("test_variant_dictionary.txt", false)
+	if err != nil {
+		t.Fatalf("Error reading dictionary: %v", err)
+	}
+
+	words := []string{}
+	for _, entry := range entries {
+		words = append(words, entry.word)
+	}
+	expected := []string{"abandon", "abandon", "#sharp-sign", "d'artagnan", "café"}
+	if !reflect.DeepEqual(expected, words) {
+		t.Logf("expected: %v\n", expected)
+		t.Logf("actual: %v\n", words)
+		t.Errorf("unexpected words")
+	}
+
+	if len(warnings) != 1 || !strings.HasPrefix(warnings[0], "test_variant_dictionary.txt:9:") {
+		t.Errorf("expected a warning for the repeated pronunciation on line 9, got %v", warnings)
+	}
+
+	_, _, err = readDictionary("test_invalid_dictionary.txt", false)
+	if err == nil {
+		t.Fatalf("expected error reading invalid dictionary")
+	}
+	for _, line := range []string{":2:", ":3:", ":4:", ":5:"} {
+		if !strings.Contains(err.Error(), "test_invalid_dictionary.txt"+line) {
+			t.Errorf("expected error for line %s, got %v", line, err)
+		}
+	}
+	if strings.Contains(err.Error(), ":1:") {
+		t.Errorf("unexpected error for valid line 1: %v", err)
+	}
+}
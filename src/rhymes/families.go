@@ -0,0 +1,105 @@
+package rhymes
+
+import (
+	"sort"
+	"strings"
+)
+
+// RhymeFamily is the corpus words that share a rhyme sound
+type RhymeFamily struct {
+	// the phonemes the words end with, from the vowel of their last rhyme
+	// syllables, e.g. "AE1 N" for "man" and "span"
+	Sound string `json:"sound"`
+
+	// the words, the most frequent in the corpus first
+	Words []string `json:"words"`
+
+	// the number of times the words appear in the corpus, all together
+	Frequency int `json:"frequency"`
+}
+
+// RhymeFamilies partitions the pronunciations of the corpus words into families
+// that rhyme at at least _minStrength_ - the words whose last _minStrength_
+// rhyme syllables are the same. A word with several pronunciations can be in
+// several families, and a word with fewer rhyme syllables isn't in any.
+//
+// The largest families come first, then the most frequent in the corpus. Every
+// family is returned, even the ones with a single word.
+func (r *Rhymer) RhymeFamilies(minStrength int) []*RhymeFamily {
+	if minStrength < 1 {
+		minStrength = 1
+	}
+
+	families := map[string]*RhymeFamily{}
+	for word, rhymes := range r.rhymes {
+		for _, rhyme := range rhymes {
+			sound := rhymeSound(rhyme.Pronunciation, minStrength)
+			if sound == "" {
+				continue
+			}
+
+			family, ok := families[sound]
+			if !ok {
+				family = &RhymeFamily{Sound: sound}
+				families[sound] = family
+			}
+
+			if !containsWord(family.Words, word) {
+				family.Words = append(family.Words, word)
+				family.Frequency += r.counts[word]
+			}
+		}
+	}
+
+	sorted := []*RhymeFamily{}
+	for _, family := range families {
+		sort.Slice(family.Words, func(i, j int) bool {
+			if r.counts[family.Words[i]] != r.counts[family.Words[j]] {
+				return r.counts[family.Words[i]] > r.counts[family.Words[j]]
+			}
+			return family.Words[i] < family.Words[j]
+		})
+		sorted = append(sorted, family)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if len(sorted[i].Words) != len(sorted[j].Words) {
+			return len(sorted[i].Words) > len(sorted[j].Words)
+		}
+		if sorted[i].Frequency != sorted[j].Frequency {
+			return sorted[i].Frequency > sorted[j].Frequency
+		}
+		return sorted[i].Sound < sorted[j].Sound
+	})
+
+	return sorted
+}
+
+// rhymeSound is the phonemes of a pronunciation from the vowel of its last
+// _syllables_ rhyme syllables, with the emphasis normalized, or empty if it has
+// fewer rhyme syllables
+func rhymeSound(pronunciation []string, syllables int) string {
+	vowels := 0
+	for i := len(pronunciation) - 1; i >= 0; i-- {
+		if !isVowelPhoneme(pronunciation[i]) {
+			continue
+		}
+
+		vowels++
+		if vowels == syllables {
+			return strings.Join(normalizeEmphasis(pronunciation[i:]), " ")
+		}
+	}
+
+	return ""
+}
+
+func containsWord(words []string, word string) bool {
+	for _, w := range words {
+		if w == word {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,64 @@
+package rhymes
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/verkestk/goetry/src/corpus"
+)
+
+func Test_rhymer_RhymeFamilies(t *testing.T) {
+	cor := &corpus.Corpus{Lines: []string{"A man can span the sound around.", "A little man, a middle man."}}
+	rhmr, err := Load("test_dictionary.txt", cor)
+	if err != nil {
+		t.Fatalf("Error loading pronunciation dictionary: %v", err)
+	}
+
+	families := rhmr.RhymeFamilies(1)
+	expected := &RhymeFamily{Sound: "AE1 N", Words: []string{"man", "can", "span"}, Frequency: 5}
+	if !reflect.DeepEqual(expected, families[0]) {
+		t.Errorf("expected first family %+v, got %+v", expected, families[0])
+	}
+
+	sounds := map[string]*RhymeFamily{}
+	for i, family := range families {
+		sounds[family.Sound] = family
+		if i > 0 && len(families[i-1].Words) < len(family.Words) {
+			t.Errorf("expected families in descending order of size")
+		}
+	}
+
+	// "around" has two pronunciations, so it's in two families
+	if !reflect.DeepEqual([]string{"around", "sound"}, sounds["AW1 N D"].Words) {
+		t.Errorf("unexpected family for AW1 N D: %+v", sounds["AW1 N D"])
+	}
+	if !reflect.DeepEqual([]string{"around"}, sounds["AW1 N"].Words) {
+		t.Errorf("unexpected family for AW1 N: %+v", sounds["AW1 N"])
+	}
+
+	// at strength 2, only words with two rhyme syllables are in families
+	families = rhmr.RhymeFamilies(2)
+	expected = &RhymeFamily{Sound: "IH1 D AH0 L", Words: []string{"middle"}, Frequency: 1}
+	found := false
+	for _, family := range families {
+		if family.Sound == expected.Sound {
+			found = reflect.DeepEqual(expected, family)
+		}
+		if containsWord(family.Words, "man") {
+			t.Errorf("expected \"man\" not to be in a family at strength 2")
+		}
+	}
+	if !found {
+		t.Errorf("expected family %+v at strength 2, got %v", expected, families)
+	}
+}
+
+func Test_rhymeSound(t *testing.T) {
+	pronunciation := []string{"B", "AA1", "D", "IY0", "G", "AA2", "R", "D"}
+	expected := map[int]string{1: "AA1 R D", 2: "IY0 G AA1 R D", 3: "AA1 D IY0 G AA1 R D", 4: ""}
+	for syllables, sound := range expected {
+		if rhymeSound(pronunciation, syllables) != sound {
+			t.Errorf("expected \"%s\" for %d syllables, got \"%s\"", sound, syllables, rhymeSound(pronunciation, syllables))
+		}
+	}
+}
@@ -0,0 +1,340 @@
+package rhymes
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// the number of times the letter to phoneme alignment is refined
+const alignmentIterations = 3
+
+// the probability given to a letter to phonemes alignment never seen in
+// training, so that any word can still be aligned
+const unseenAlignment = 1e-6
+
+// guesser predicts the pronunciation of a word from its spelling, using rules
+// learned from a pronunciation dictionary.
+//
+// Training lines up the letters of each dictionary word with its phonemes - each
+// letter is silent, or says one or two phonemes. Then, for every letter, it
+// remembers the phonemes said most often in each context of the letters around
+// it. The stress of a guessed pronunciation is the pattern most often used by
+// dictionary words with the same number of syllables.
+type guesser struct {
+	// the phonemes (without stress) most often said by a letter in a context -
+	// see contexts for the keys
+	rules map[string]string
+
+	// the phonemes that are vowels, without stress
+	vowels map[string]bool
+
+	// for each number of syllables, the most common stress pattern
+	stresses map[int]string
+}
+
+// the letters of a dictionary word, and the phonemes (without stress) of one of
+// its pronunciations
+type spelling struct {
+	letters  []rune
+	phonemes []string
+}
+
+// a letter and the phonemes it says, e.g. "x" and "K S"
+type alignment struct {
+	letter   rune
+	phonemes string
+}
+
+func newGuesser(pronunciationMap map[string][][]string) *guesser {
+	g := &guesser{rules: map[string]string{}, vowels: map[string]bool{}, stresses: map[int]string{}}
+
+	examples := []*spelling{}
+	stressCounts := map[int]map[string]int{}
+	for word, pronunciations := range pronunciationMap {
+		letters := []rune(word)
+		if !guessable(letters) {
+			continue
+		}
+
+		for _, pronunciation := range pronunciations {
+			if len(pronunciation) == 0 {
+				continue
+			}
+
+			phonemes := []string{}
+			for _, phoneme := range pronunciation {
+				if isVowelPhoneme(phoneme) {
+					g.vowels[phoneme[:len(phoneme)-1]] = true
+				}
+				phonemes = append(phonemes, strings.TrimRightFunc(phoneme, unicode.IsDigit))
+			}
+			examples = append(examples, &spelling{letters: letters, phonemes: phonemes})
+
+			pattern := stressPattern(pronunciation)
+			if stressCounts[len(pattern)] == nil {
+				stressCounts[len(pattern)] = map[string]int{}
+			}
+			stressCounts[len(pattern)][pattern]++
+		}
+	}
+
+	for syllables, counts := range stressCounts {
+		g.stresses[syllables] = mostCommon(counts)
+	}
+
+	// start with letters and phonemes that appear in roughly the same place in
+	// a word, and then refine with the alignments that start gives
+	probabilities := initialAlignmentProbabilities(examples)
+
+	var aligned [][]alignment
+	for i := 0; i < alignmentIterations; i++ {
+		aligned = [][]alignment{}
+		counts := map[rune]map[string]int{}
+		for _, e := range examples {
+			alignments := align(e.letters, e.phonemes, probabilities)
+			if alignments == nil {
+				continue
+			}
+
+			aligned = append(aligned, alignments)
+			for _, a := range alignments {
+				if counts[a.letter] == nil {
+					counts[a.letter] = map[string]int{}
+				}
+				counts[a.letter][a.phonemes]++
+			}
+		}
+
+		probabilities = map[rune]map[string]float64{}
+		for letter, phonemeCounts := range counts {
+			total := 0
+			for _, count := range phonemeCounts {
+				total += count
+			}
+			probabilities[letter] = map[string]float64{}
+			for phonemes, count := range phonemeCounts {
+				probabilities[letter][phonemes] = float64(count) / float64(total)
+			}
+		}
+	}
+
+	ruleCounts := map[string]map[string]int{}
+	for _, alignments := range aligned {
+		letters := []rune{}
+		for _, a := range alignments {
+			letters = append(letters, a.letter)
+		}
+
+		for i, a := range alignments {
+			for _, context := range contexts(letters, i) {
+				if ruleCounts[context] == nil {
+					ruleCounts[context] = map[string]int{}
+				}
+				ruleCounts[context][a.phonemes]++
+			}
+		}
+	}
+
+	for context, counts := range ruleCounts {
+		g.rules[context] = mostCommon(counts)
+	}
+
+	return g
+}
+
+// guess predicts the pronunciation of a word. Returns nil if the word can't be
+// guessed, like a word with digits in it, or if the guess has no vowels.
+func (g *guesser) guess(word string) []string {
+	letters := []rune(strings.ToLower(word))
+	if !guessable(letters) {
+		return nil
+	}
+
+	pronunciation := []string{}
+	for i := range letters {
+		for _, context := range contexts(letters, i) {
+			phonemes, ok := g.rules[context]
+			if ok {
+				pronunciation = append(pronunciation, strings.Fields(phonemes)...)
+				break
+			}
+		}
+	}
+
+	syllables := 0
+	for _, phoneme := range pronunciation {
+		if g.vowels[phoneme] {
+			syllables++
+		}
+	}
+	if syllables == 0 {
+		return nil
+	}
+
+	// without a pattern for this many syllables, stress the first syllable
+	pattern, ok := g.stresses[syllables]
+	if !ok {
+		pattern = "1" + strings.Repeat("0", syllables-1)
+	}
+
+	vowel := 0
+	for i, phoneme := range pronunciation {
+		if g.vowels[phoneme] {
+			pronunciation[i] = phoneme + pattern[vowel:vowel+1]
+			vowel++
+		}
+	}
+
+	return pronunciation
+}
+
+// guessable checks whether a word is made of letters (and apostrophes) only
+func guessable(letters []rune) bool {
+	if len(letters) == 0 {
+		return false
+	}
+
+	for _, letter := range letters {
+		if !unicode.IsLetter(letter) && letter != '\'' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// contexts returns the keys for the letter at index i of a word, from the most
+// specific context to the least: the letter with the one before it and two
+// after it, with one on each side, with the one after, with the one before,
+// and on its own. "#" marks the edges of the word.
+func contexts(letters []rune, i int) []string {
+	at := func(j int) string {
+		if j < 0 || j >= len(letters) {
+			return "#"
+		}
+		return string(letters[j])
+	}
+
+	before, letter, after, afterNext := at(i-1), at(i), at(i+1), at(i+2)
+	return []string{
+		"4:" + before + letter + after + afterNext,
+		"3:" + before + letter + after,
+		"2:" + letter + after,
+		"2-:" + before + letter,
+		"1:" + letter,
+	}
+}
+
+// initialAlignmentProbabilities estimates how likely each letter is to say each
+// phoneme, by counting the phonemes close to the same relative position in the
+// word as the letter. Saying two phonemes, or nothing, is always unlikely.
+func initialAlignmentProbabilities(examples []*spelling) map[rune]map[string]float64 {
+	counts := map[rune]map[string]float64{}
+	for _, e := range examples {
+		for i, letter := range e.letters {
+			if counts[letter] == nil {
+				counts[letter] = map[string]float64{}
+			}
+
+			position := float64(i) * float64(len(e.phonemes)) / float64(len(e.letters))
+			for j, phoneme := range e.phonemes {
+				if math.Abs(position-float64(j)) <= 1 {
+					counts[letter][phoneme]++
+				}
+			}
+		}
+	}
+
+	probabilities := map[rune]map[string]float64{}
+	for letter, phonemeCounts := range counts {
+		total := 0.0
+		for _, count := range phonemeCounts {
+			total += count
+		}
+
+		probabilities[letter] = map[string]float64{"": 0.1}
+		for phoneme, count := range phonemeCounts {
+			probabilities[letter][phoneme] = count / total
+		}
+		for phoneme1, count1 := range phonemeCounts {
+			for phoneme2, count2 := range phonemeCounts {
+				probabilities[letter][phoneme1+" "+phoneme2] = 0.01 * count1 / total * count2 / total
+			}
+		}
+	}
+
+	return probabilities
+}
+
+// align finds the most probable way for the letters of a word to say its
+// phonemes, in order, with each letter silent or saying one or two phonemes.
+// Returns nil if there is no way, e.g. when there are too many phonemes.
+func align(letters []rune, phonemes []string, probabilities map[rune]map[string]float64) []alignment {
+	if len(phonemes) > 2*len(letters) {
+		return nil
+	}
+
+	// cost[i][j] is the cost of the best alignment of the first i letters with the
+	// first j phonemes, and said[i][j] the number of phonemes letter i-1 says in it
+	cost := make([][]float64, len(letters)+1)
+	said := make([][]int, len(letters)+1)
+	for i := range cost {
+		cost[i] = make([]float64, len(phonemes)+1)
+		said[i] = make([]int, len(phonemes)+1)
+		for j := range cost[i] {
+			cost[i][j] = math.Inf(1)
+		}
+	}
+	cost[0][0] = 0
+
+	for i := 1; i <= len(letters); i++ {
+		for j := 0; j <= len(phonemes); j++ {
+			for n := 0; n <= 2 && n <= j; n++ {
+				if math.IsInf(cost[i-1][j-n], 1) {
+					continue
+				}
+
+				probability := probabilities[letters[i-1]][strings.Join(phonemes[j-n:j], " ")]
+				if probability == 0 {
+					probability = unseenAlignment
+				}
+
+				c := cost[i-1][j-n] - math.Log(probability)
+				if c < cost[i][j] {
+					cost[i][j] = c
+					said[i][j] = n
+				}
+			}
+		}
+	}
+
+	if math.IsInf(cost[len(letters)][len(phonemes)], 1) {
+		return nil
+	}
+
+	alignments := make([]alignment, len(letters))
+	j := len(phonemes)
+	for i := len(letters); i > 0; i-- {
+		n := said[i][j]
+		alignments[i-1] = alignment{letter: letters[i-1], phonemes: strings.Join(phonemes[j-n:j], " ")}
+		j -= n
+	}
+
+	return alignments
+}
+
+// mostCommon returns the key with the highest count, breaking ties by the
+// lowest key so that the result doesn't depend on map order
+func mostCommon(counts map[string]int) string {
+	best := ""
+	bestCount := -1
+	for key, count := range counts {
+		if count > bestCount || (count == bestCount && key < best) {
+			best = key
+			bestCount = count
+		}
+	}
+
+	return best
+}
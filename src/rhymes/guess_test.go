@@ -0,0 +1,89 @@
+package rhymes
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/verkestk/goetry/src/corpus"
+)
+
+func Test_guesser_guess(t *testing.T) {
+	pronunciationMap := map[string][][]string{
+		"betty":     [][]string{[]string{"B", "EH1", "T", "IY0"}},
+		"street":    [][]string{[]string{"S", "T", "R", "IY1", "T"}},
+		"attention": [][]string{[]string{"AH0", "T", "EH1", "N", "SH", "AH0", "N"}},
+		"moonlight": [][]string{[]string{"M", "UW1", "N", "L", "AY2", "T"}},
+		"fox":       [][]string{[]string{"F", "AA1", "K", "S"}},
+	}
+	g := newGuesser(pronunciationMap)
+
+	// words the guesser was trained on are pronounced as in the dictionary
+	for _, word := range []string{"betty", "street", "attention"} {
+		guess := g.guess(word)
+		if !reflect.DeepEqual(pronunciationMap[word][0], guess) {
+			t.Errorf("expected %v for \"%s\", got %v", pronunciationMap[word][0], word, guess)
+		}
+	}
+
+	guess := g.guess("STREETS")
+	if strings.Join(guess, " ") != "S T R IY1 T S" {
+		t.Errorf("expected [S T R IY1 T S] for \"STREETS\", got %v", guess)
+	}
+
+	for _, word := range []string{"1999", "", "x-ray"} {
+		if g.guess(word) != nil {
+			t.Errorf("expected no guess for \"%s\", got %v", word, g.guess(word))
+		}
+	}
+}
+
+func Test_align(t *testing.T) {
+	probabilities := map[rune]map[string]float64{
+		'f': map[string]float64{"F": 1},
+		'o': map[string]float64{"AA": 1},
+		'x': map[string]float64{"K S": 1},
+	}
+
+	expected := []alignment{alignment{'f', "F"}, alignment{'o', "AA"}, alignment{'x', "K S"}}
+	actual := align([]rune("fox"), []string{"F", "AA", "K", "S"}, probabilities)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Logf("expected: %v\n", expected)
+		t.Logf("actual: %v\n", actual)
+		t.Errorf("unexpected alignment")
+	}
+
+	if align([]rune("x"), []string{"EH", "K", "S"}, probabilities) != nil {
+		t.Errorf("expected no alignment of 3 phonemes to 1 letter")
+	}
+}
+
+func Test_LoadWithOptions_GuessPronunciations(t *testing.T) {
+	cor, _, _ := corpus.Load("../corpus/test_corpus.json", "")
+	rhmr, err := LoadWithOptions("test_dictionary.txt", cor, Options{GuessPronunciations: true})
+	if err != nil {
+		t.Fatalf("Error loading pronunciation dictionary: %v", err)
+	}
+
+	// guessed words are still reported as unknown
+	if len(rhmr.UnknownPronunciations()) != 5 {
+		t.Errorf("expected 5 unknown words, got %v", rhmr.UnknownPronunciations())
+	}
+
+	for _, word := range rhmr.UnknownPronunciations() {
+		pronunciations := rhmr.Pronunciations(word)
+		if len(pronunciations) != 1 || !strings.Contains(stressPattern(pronunciations[0]), "1") {
+			t.Errorf("expected a guessed pronunciation with a primary stress for \"%s\", got %v", word, pronunciations)
+		}
+	}
+
+	for _, rhyme := range rhmr.Rhymes("roly", rhmr.Pronunciations("roly")[0], 1) {
+		if rhyme.Guessed != (rhyme.Word == "beerbelly" || rhyme.Word == "currency") {
+			t.Errorf("unexpected Guessed %t for rhyme \"%s\"", rhyme.Guessed, rhyme.Word)
+		}
+	}
+
+	if rhmr.Syllables("currency") == nil {
+		t.Errorf("expected syllables for guessed word \"currency\"")
+	}
+}
@@ -0,0 +1,107 @@
+package rhymes
+
+import (
+	"strings"
+)
+
+// rhymeIndex finds the rhymes for a pronunciation without comparing it to every
+// pronunciation in the corpus. It's a trie of the rhyme syllables of every
+// pronunciation, read from the last syllable to the first, so that all the
+// pronunciations sharing their last n rhyme syllables are under the same node
+// at depth n.
+type rhymeIndex struct {
+	root *rhymeIndexNode
+}
+
+type rhymeIndexNode struct {
+	// the next rhyme syllable back, e.g. "AE1T" for "AH0" in "BETTER"
+	children map[string]*rhymeIndexNode
+
+	// the pronunciations with no more rhyme syllables
+	rhymes []*Rhyme
+}
+
+func newRhymeIndex() *rhymeIndex {
+	return &rhymeIndex{root: &rhymeIndexNode{children: map[string]*rhymeIndexNode{}}}
+}
+
+// add indexes a corpus word's pronunciation. Pronunciations without a vowel
+// can't rhyme, and aren't indexed.
+func (index *rhymeIndex) add(rhyme *Rhyme) {
+	syllables := getRhymeSyllables(rhyme.Pronunciation)
+	if len(syllables) == 0 {
+		return
+	}
+
+	node := index.root
+	for i := len(syllables) - 1; i >= 0; i-- {
+		child, ok := node.children[syllables[i]]
+		if !ok {
+			child = &rhymeIndexNode{children: map[string]*rhymeIndexNode{}}
+			node.children[syllables[i]] = child
+		}
+		node = child
+	}
+	node.rhymes = append(node.rhymes, rhyme)
+}
+
+// rhymes returns the indexed pronunciations that rhyme with pronunciation at
+// at least minStrength (which must be at least 1), like rhymeStrength, each
+// with its strength. The word itself, and other words pronounced the same, are
+// left out.
+func (index *rhymeIndex) rhymes(word string, pronunciation []string, minStrength int) []*Rhyme {
+	syllables := getRhymeSyllables(pronunciation)
+
+	// follow the syllables back as far as the index has them
+	path := []*rhymeIndexNode{index.root}
+	for i := len(syllables) - 1; i >= 0; i-- {
+		child, ok := path[len(path)-1].children[syllables[i]]
+		if !ok {
+			break
+		}
+		path = append(path, child)
+	}
+
+	// a pronunciation under the node at depth n, but not under the next node on
+	// the path, shares exactly n rhyme syllables - unless it's under the deepest
+	// node, which shares all of them
+	word = strings.ToLower(word)
+	sound := strings.Join(normalizeEmphasis(pronunciation), " ")
+	found := []*Rhyme{}
+	collect := func(rhyme *Rhyme, strength int) {
+		if strings.ToLower(rhyme.Word) == word || strings.Join(normalizeEmphasis(rhyme.Pronunciation), " ") == sound {
+			return
+		}
+		score, kind := RhymeScore(pronunciation, rhyme.Pronunciation)
+		found = append(found, &Rhyme{Word: rhyme.Word, Pronunciation: rhyme.Pronunciation, Strength: strength, Guessed: rhyme.Guessed, Derived: rhyme.Derived, Kind: kind, Score: score})
+	}
+
+	deepest := len(path) - 1
+	for strength := deepest; strength >= minStrength; strength-- {
+		node := path[strength]
+		for _, rhyme := range node.rhymes {
+			collect(rhyme, strength)
+		}
+
+		for _, child := range node.children {
+			if strength < deepest && child == path[strength+1] {
+				continue
+			}
+			child.walk(func(rhyme *Rhyme) {
+				collect(rhyme, strength)
+			})
+		}
+	}
+
+	return found
+}
+
+// walk calls visit for every pronunciation under a node
+func (node *rhymeIndexNode) walk(visit func(*Rhyme)) {
+	for _, rhyme := range node.rhymes {
+		visit(rhyme)
+	}
+	for _, child := range node.children {
+		child.walk(visit)
+	}
+}
@@ -0,0 +1,52 @@
+package rhymes
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/verkestk/goetry/src/corpus"
+)
+
+func Test_rhymeIndex_rhymes(t *testing.T) {
+	cor, _, _ := corpus.Load("../corpus/test_corpus.json", "")
+	rhmr, err := LoadWithOptions("test_dictionary.txt", cor, Options{GuessPronunciations: true})
+	if err != nil {
+		t.Fatalf("Error loading pronunciation dictionary: %v", err)
+	}
+
+	// the index finds exactly what comparing every pronunciation finds
+	queries := 0
+	for word := range rhmr.rhymes {
+		for _, pronunciation := range rhmr.Pronunciations(word) {
+			for strength := 1; strength <= 3; strength++ {
+				expected := scanRhymes(rhmr.rhymes, word, pronunciation, strength)
+				actual := rhmr.index.rhymes(word, pronunciation, strength)
+				sortRhymes(expected)
+				sortRhymes(actual)
+				if !reflect.DeepEqual(expected, actual) {
+					t.Errorf("unexpected rhymes for \"%s\" (%v) at strength %d: expected %d, got %d", word, pronunciation, strength, len(expected), len(actual))
+				}
+				queries++
+			}
+		}
+	}
+	if queries == 0 {
+		t.Errorf("expected rhymes to check")
+	}
+
+	// words outside the corpus can be looked up too
+	expected := scanRhymes(rhmr.rhymes, "regretty", []string{"R", "IH0", "G", "R", "EH1", "T", "IY0"}, 2)
+	actual := rhmr.index.rhymes("regretty", []string{"R", "IH0", "G", "R", "EH1", "T", "IY0"}, 2)
+	if len(actual) == 0 || len(expected) != len(actual) {
+		t.Errorf("expected %d rhymes for \"regretty\", got %d", len(expected), len(actual))
+	}
+}
+
+// sorts rhymes completely, including different pronunciations of a word
+func sortRhymes(rhymes []*Rhyme) {
+	sort.Slice(rhymes, func(i, j int) bool {
+		return fmt.Sprint(rhymes[i]) < fmt.Sprint(rhymes[j])
+	})
+}
@@ -0,0 +1,216 @@
+package rhymes
+
+import (
+	"sort"
+	"strings"
+)
+
+// the most words in a phrase from the corpus that can rhyme
+const maxMosaicWords = 3
+
+// punctuation ending a clause of a corpus line, so that phrases don't run from
+// one clause into the next
+const clausePunctuation = ".,;:!?"
+
+// unstressed vowels that are pronounced much alike, all treated as a schwa when
+// comparing mosaic rhymes
+var reducedVowels = map[string]bool{"AH0": true, "IH0": true, "EH0": true, "UH0": true}
+
+// PhrasePronunciations provides the pronunciations of a word or a phrase of
+// several words. A phrase is pronounced as its words one after another, with a
+// last word of one syllable unstressed when it follows a stressed syllable, like
+// "it" in "know it". Returns nil if any of the words has no known pronunciation.
+func (r *Rhymer) PhrasePronunciations(phrase string) [][]string {
+	words := Tokenize(phrase)
+	if len(words) == 1 {
+		return r.Pronunciations(words[0])
+	}
+
+	return phrasePronunciations(words, r.Pronunciations)
+}
+
+// MosaicRhymes returns the corpus words, and the phrases of up to 3 words ending
+// a line or a clause of the corpus, that rhyme with a word or phrase at at least
+// _minStrength_, ordered by strength. Unstressed vowels like those of "poet"
+// and "know it" count as the same sound. Words and phrases ending with the same
+// word as the word or phrase are left out.
+//
+// Unlike Rhymes, it compares the pronunciation with every word and phrase in
+// the corpus. The phrases are found the first time it's called.
+func (r *Rhymer) MosaicRhymes(phrase string, pronunciation []string, minStrength int) []*Rhyme {
+	words := Tokenize(strings.ToLower(phrase))
+	if len(words) == 0 {
+		return []*Rhyme{}
+	}
+	last := words[len(words)-1]
+
+	found := []*Rhyme{}
+	check := func(candidate string, candidatePronunciation []string, rhyme *Rhyme) {
+		candidateWords := strings.Fields(candidate)
+		if candidateWords[len(candidateWords)-1] == last {
+			return
+		}
+
+		strength := mosaicStrength(strings.Join(words, " "), candidate, pronunciation, candidatePronunciation)
+		if strength < minStrength {
+			return
+		}
+
+		score, kind := RhymeScore(reduceVowels(pronunciation), reduceVowels(candidatePronunciation))
+		found = append(found, &Rhyme{Word: candidate, Pronunciation: candidatePronunciation, Strength: strength, Guessed: rhyme.Guessed, Derived: rhyme.Derived, Kind: kind, Score: score})
+	}
+
+	for word, rhymes := range r.rhymes {
+		for _, rhyme := range rhymes {
+			check(word, rhyme.Pronunciation, rhyme)
+		}
+	}
+	if r.phrases == nil {
+		r.phrases = r.corpusPhrases(r.lines)
+	}
+	for candidate, pronunciations := range r.phrases {
+		for _, candidatePronunciation := range pronunciations {
+			check(candidate, candidatePronunciation, &Rhyme{})
+		}
+	}
+
+	sort.Sort(byStrengthDesc(found))
+
+	return found
+}
+
+// corpusPhrases returns the pronunciations of the phrases of 2 to 3 words that
+// end a line, or a clause, of the corpus - a clause ends with punctuation like a
+// comma or a period. Phrases with a word without a known pronunciation are left
+// out.
+func (r *Rhymer) corpusPhrases(lines []string) map[string][][]string {
+	phrases := map[string][][]string{}
+	for _, line := range lines {
+		words := []string{}
+		ends := []bool{}
+		for _, field := range strings.Fields(line) {
+			fieldWords := Tokenize(strings.ToLower(field))
+			if len(fieldWords) == 0 {
+				continue
+			}
+
+			words = append(words, fieldWords...)
+			for range fieldWords {
+				ends = append(ends, false)
+			}
+			trimmed := strings.TrimRight(field, "\"')]")
+			if trimmed != "" && strings.ContainsAny(trimmed[len(trimmed)-1:], clausePunctuation) {
+				ends[len(ends)-1] = true
+			}
+		}
+		if len(ends) > 0 {
+			ends[len(ends)-1] = true
+		}
+
+		for i, end := range ends {
+			if !end {
+				continue
+			}
+
+			for n := 2; n <= maxMosaicWords && n <= i+1; n++ {
+				if ends[i-n+1] {
+					// the phrase would start in the clause before
+					break
+				}
+
+				phrase := strings.Join(words[i-n+1:i+1], " ")
+				if _, ok := phrases[phrase]; ok {
+					continue
+				}
+
+				pronunciations := phrasePronunciations(words[i-n+1:i+1], r.Pronunciations)
+				if pronunciations != nil {
+					phrases[phrase] = pronunciations
+				}
+			}
+		}
+	}
+
+	return phrases
+}
+
+// phrasePronunciations puts together the pronunciations of the words of a
+// phrase (see PhrasePronunciations), keeping at most 8. lookup returns the
+// pronunciations of a word.
+func phrasePronunciations(words []string, lookup func(string) [][]string) [][]string {
+	if len(words) == 0 {
+		return nil
+	}
+
+	pronunciations := [][]string{nil}
+	for i, word := range words {
+		wordPronunciations := lookup(word)
+		if len(wordPronunciations) == 0 {
+			return nil
+		}
+
+		next := [][]string{}
+		seen := map[string]bool{}
+		for _, start := range pronunciations {
+			for _, pronunciation := range wordPronunciations {
+				if i > 0 && i == len(words)-1 && countSyllables(pronunciation) == 1 && endsStressed(start) {
+					pronunciation = unstressed(pronunciation)
+				}
+
+				combined := append(append([]string{}, start...), pronunciation...)
+				key := strings.Join(combined, " ")
+				if !seen[key] && len(next) < maxDerivedPronunciations {
+					seen[key] = true
+					next = append(next, combined)
+				}
+			}
+		}
+		pronunciations = next
+	}
+
+	return pronunciations
+}
+
+// mosaicStrength is the strength of a rhyme (see rhymeStrength) with
+// unstressed vowels reduced to the same sound
+func mosaicStrength(phrase1, phrase2 string, pronunciation1, pronunciation2 []string) int {
+	return rhymeStrength(phrase1, phrase2, reduceVowels(pronunciation1), reduceVowels(pronunciation2))
+}
+
+// endsStressed checks whether the last vowel of a pronunciation is stressed
+func endsStressed(pronunciation []string) bool {
+	for i := len(pronunciation) - 1; i >= 0; i-- {
+		if isVowelPhoneme(pronunciation[i]) {
+			return !strings.HasSuffix(pronunciation[i], "0")
+		}
+	}
+
+	return false
+}
+
+// unstressed is a pronunciation with all of its vowels unstressed
+func unstressed(pronunciation []string) []string {
+	result := []string{}
+	for _, phoneme := range pronunciation {
+		if isVowelPhoneme(phoneme) {
+			phoneme = phoneme[:len(phoneme)-1] + "0"
+		}
+		result = append(result, phoneme)
+	}
+
+	return result
+}
+
+// reduceVowels is a pronunciation with the unstressed vowels that sound alike
+// all made a schwa
+func reduceVowels(pronunciation []string) []string {
+	result := []string{}
+	for _, phoneme := range pronunciation {
+		if reducedVowels[phoneme] {
+			phoneme = "AH0"
+		}
+		result = append(result, phoneme)
+	}
+
+	return result
+}
@@ -0,0 +1,109 @@
+package rhymes
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/verkestk/goetry/src/corpus"
+)
+
+func loadMosaicRhymer(t *testing.T) *Rhymer {
+	cor := &corpus.Corpus{Lines: []string{
+		"I know it.",
+		"The body's hard, I know",
+		"A poet",
+		"A bodyguard",
+	}}
+
+	rhmr, err := Load("test_mosaic_dictionary.txt", cor)
+	if err != nil {
+		t.Fatalf("Error loading pronunciation dictionary: %v", err)
+	}
+
+	return rhmr
+}
+
+func Test_rhymer_PhrasePronunciations(t *testing.T) {
+	rhmr := loadMosaicRhymer(t)
+
+	expected := [][]string{[]string{"N", "OW1", "IH0", "T"}}
+	if !reflect.DeepEqual(expected, rhmr.PhrasePronunciations("know it")) {
+		t.Errorf("expected %v, got %v", expected, rhmr.PhrasePronunciations("know it"))
+	}
+
+	// "hard" keeps its stress after an unstressed syllable
+	expected = [][]string{[]string{"B", "AA1", "D", "IY0", "Z", "HH", "AA1", "R", "D"}}
+	if !reflect.DeepEqual(expected, rhmr.PhrasePronunciations("body's hard")) {
+		t.Errorf("expected %v, got %v", expected, rhmr.PhrasePronunciations("body's hard"))
+	}
+
+	expected = [][]string{[]string{"P", "OW1", "AH0", "T"}}
+	if !reflect.DeepEqual(expected, rhmr.PhrasePronunciations("poet")) {
+		t.Errorf("expected %v, got %v", expected, rhmr.PhrasePronunciations("poet"))
+	}
+
+	if rhmr.PhrasePronunciations("know stuff") != nil {
+		t.Errorf("expected no pronunciation for a phrase with an unknown word")
+	}
+}
+
+func Test_rhymer_corpusPhrases(t *testing.T) {
+	rhmr := loadMosaicRhymer(t)
+	if rhmr.phrases != nil {
+		t.Fatalf("expected no phrases before searching mosaic rhymes, got %d", len(rhmr.phrases))
+	}
+	rhmr.MosaicRhymes("poet", rhmr.Pronunciations("poet")[0], 1)
+
+	phrases := []string{}
+	for phrase := range rhmr.phrases {
+		phrases = append(phrases, phrase)
+	}
+	sort.Strings(phrases)
+
+	// no phrase runs across the comma after "hard"
+	expected := []string{"a bodyguard", "a poet", "body's hard", "i know", "i know it", "know it", "the body's hard"}
+	if !reflect.DeepEqual(expected, phrases) {
+		t.Errorf("expected phrases %v, got %v", expected, phrases)
+	}
+}
+
+func Test_rhymer_MosaicRhymes(t *testing.T) {
+	rhmr := loadMosaicRhymer(t)
+
+	strengths := func(phrase string, minStrength int) map[string]int {
+		found := map[string]int{}
+		rhymes := rhmr.MosaicRhymes(phrase, rhmr.PhrasePronunciations(phrase)[0], minStrength)
+		for i, rhyme := range rhymes {
+			found[rhyme.Word] = rhyme.Strength
+			if i > 0 && rhymes[i-1].Strength < rhyme.Strength {
+				t.Errorf("expected rhymes for \"%s\" in descending order of strength", phrase)
+			}
+		}
+		return found
+	}
+
+	poet := strengths("poet", 1)
+	if poet["know it"] != 2 || poet["i know it"] != 2 {
+		t.Errorf("expected \"poet\" to rhyme with \"know it\" and \"i know it\" at strength 2, got %v", poet)
+	}
+	if _, ok := poet["a poet"]; ok {
+		t.Errorf("expected \"poet\" not to rhyme with a phrase ending in \"poet\"")
+	}
+
+	knowIt := strengths("know it", 2)
+	if knowIt["poet"] != 2 || knowIt["a poet"] != 2 {
+		t.Errorf("expected \"know it\" to rhyme with \"poet\" and \"a poet\" at strength 2, got %v", knowIt)
+	}
+	if _, ok := knowIt["i know it"]; ok {
+		t.Errorf("expected \"know it\" not to rhyme with a phrase ending in \"it\"")
+	}
+
+	bodysHard := strengths("body's hard", 1)
+	if bodysHard["bodyguard"] != 1 || bodysHard["a bodyguard"] != 1 {
+		t.Errorf("expected \"body's hard\" to rhyme with \"bodyguard\", got %v", bodysHard)
+	}
+	if _, ok := bodysHard["hard"]; ok {
+		t.Errorf("expected \"body's hard\" not to rhyme with \"hard\"")
+	}
+}
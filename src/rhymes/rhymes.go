@@ -2,8 +2,6 @@ package rhymes
 
 import (
 	"fmt"
-	"io/ioutil"
-	"reflect"
 	"sort"
 	"strings"
 	"unicode"
@@ -22,13 +20,74 @@ type Rhyme struct {
 
 	// the strength of the rhyme (roughly number of rhyming syllables)
 	Strength int
+
+	// whether the Pronunciation was guessed from the spelling of the Word,
+	// because the Word isn't in the pronunciation dictionary
+	Guessed bool
+
+	// whether the Pronunciation was put together from the pronunciations of the
+	// parts of a compound or hyphenated Word, because the Word isn't in the
+	// pronunciation dictionary
+	Derived bool
+
+	// the kind of rhyme, for a Rhyme found by Rhymes or SlantRhymes
+	Kind RhymeKind
+
+	// how well the rhyme matches, from 0 to 1 (see RhymeScore), for a Rhyme
+	// found by Rhymes or SlantRhymes
+	Score float64
+}
+
+// Options changes how a Rhymer is loaded
+type Options struct {
+	// guess the pronunciation of corpus words missing from the pronunciation
+	// dictionary from their spelling, rather than leaving them unknown
+	GuessPronunciations bool
+
+	// paths to extra pronunciation dictionaries, applied in order on top of the
+	// pronunciation dictionary - see ApplyExtraDictionary
+	ExtraDictionaries []string
 }
 
+// lines of an extra dictionary starting with this delete pronunciations
+const deletePrefix = "-"
+
 // Rhymer provides functions for getting pronunciations, finding rhyming words
 // from a corpus, and finding words in corpus missing pronunciation data.
 type Rhymer struct {
 	rhymes  map[string][]*Rhyme
 	missing map[string]bool
+
+	// the number of times each word appears in the corpus
+	counts map[string]int
+
+	// words missing from the pronunciation dictionary, but added by an extra
+	// dictionary
+	resolved map[string]bool
+
+	// words missing from the pronunciation dictionary, but with a pronunciation
+	// derived from their parts
+	derived map[string]bool
+
+	// problems found reading the pronunciation dictionaries that didn't stop
+	// them loading
+	warnings []string
+
+	// every pronunciation in rhymes, indexed by rhyme syllables
+	index *rhymeIndex
+
+	// the lines of the corpus, and the pronunciations of the phrases ending its
+	// lines and clauses, found the first time mosaic rhymes are searched
+	lines   []string
+	phrases map[string][][]string
+
+	// every pronunciation of the pronunciation dictionaries, so that any
+	// dictionary word can be rhymed
+	dictionary map[string][][]string
+
+	// every pronunciation in dictionary and rhymes, indexed by rhyme syllables
+	// the first time the whole dictionary is searched
+	dictionaryIndex *rhymeIndex
 }
 
 type byStrengthDesc []*Rhyme
@@ -49,7 +108,13 @@ func (s byStrengthDesc) Less(i, j int) bool {
 // Load creates a Rhymer based on a corpus, using a specific rhyming dictionary
 // It finds rhymes
 func Load(pronunciationDictionaryFilepath string, corpus *corpus.Corpus) (*Rhymer, error) {
-	bytes, err := ioutil.ReadFile(pronunciationDictionaryFilepath)
+	return LoadWithOptions(pronunciationDictionaryFilepath, corpus, Options{})
+}
+
+// LoadWithOptions creates a Rhymer like Load, changing how it's loaded with
+// options
+func LoadWithOptions(pronunciationDictionaryFilepath string, corpus *corpus.Corpus, options Options) (*Rhymer, error) {
+	entries, warnings, err := readDictionary(pronunciationDictionaryFilepath, false)
 	if err != nil {
 		return nil, fmt.Errorf("error loading pronunciation dictionary: %w", err)
 	}
@@ -57,28 +122,31 @@ func Load(pronunciationDictionaryFilepath string, corpus *corpus.Corpus) (*Rhyme
 	// map the pronunciations for everythingg in the dictionary for quick
 	// reference
 	pronunciationMap := map[string][][]string{}
+	for _, entry := range entries {
+		pronunciationMap[entry.word] = append(pronunciationMap[entry.word], entry.pronunciation)
+	}
 
-	lines := strings.Split(string(bytes), "\n")
-
-	for _, line := range lines {
-		word, pronunciation := getPronunciationFromDictionary(line)
-		_, ok := pronunciationMap[word]
-		if !ok {
-			pronunciationMap[word] = [][]string{pronunciation}
-		} else {
-			pronunciationMap[word] = append(pronunciationMap[word], pronunciation)
+	var baseWords map[string]bool
+	if len(options.ExtraDictionaries) > 0 {
+		baseWords = map[string]bool{}
+		for word := range pronunciationMap {
+			baseWords[word] = true
+		}
+	}
+	for _, extraDictionaryFilepath := range options.ExtraDictionaries {
+		extraWarnings, err := applyExtraDictionary(pronunciationMap, extraDictionaryFilepath)
+		if err != nil {
+			return nil, err
 		}
+		warnings = append(warnings, extraWarnings...)
 	}
 
 	// get all of the words from the corpus and save all of their pronunciations
 	// in a *rhymer
-	rhmr := &Rhymer{rhymes: make(map[string][]*Rhyme), missing: make(map[string]bool)}
+	rhmr := &Rhymer{rhymes: make(map[string][]*Rhyme), missing: make(map[string]bool), resolved: make(map[string]bool), derived: make(map[string]bool), warnings: warnings, dictionary: pronunciationMap, counts: map[string]int{}}
 	for _, line := range corpus.Lines {
-		// tokenize by all non letter/numbers (excluding apostrophes)
-		words := strings.FieldsFunc(line, func(c rune) bool {
-			return !unicode.IsLetter(c) && !unicode.IsNumber(c) && c != '\''
-		})
-		for _, word := range words {
+		for _, word := range Tokenize(line) {
+			rhmr.counts[strings.ToLower(word)]++
 			_, ok := rhmr.rhymes[strings.ToLower(word)]
 			if !ok {
 				rhymes := []*Rhyme{}
@@ -87,6 +155,9 @@ func Load(pronunciationDictionaryFilepath string, corpus *corpus.Corpus) (*Rhyme
 					for _, pronunciation := range pronunciations {
 						rhymes = append(rhymes, &Rhyme{Word: strings.ToLower(word), Pronunciation: pronunciation})
 					}
+					if baseWords != nil && !baseWords[strings.ToLower(word)] {
+						rhmr.resolved[strings.ToLower(word)] = true
+					}
 				} else {
 					rhmr.missing[strings.ToLower(word)] = true
 				}
@@ -95,12 +166,51 @@ func Load(pronunciationDictionaryFilepath string, corpus *corpus.Corpus) (*Rhyme
 		}
 	}
 
+	lookup := func(word string) [][]string {
+		return pronunciationMap[word]
+	}
+	for word := range rhmr.missing {
+		pronunciations := deriveCompound(word, lookup)
+		if pronunciations == nil {
+			continue
+		}
+
+		for _, pronunciation := range pronunciations {
+			rhmr.rhymes[word] = append(rhmr.rhymes[word], &Rhyme{Word: word, Pronunciation: pronunciation, Derived: true})
+		}
+		rhmr.derived[word] = true
+		delete(rhmr.missing, word)
+	}
+
+	if options.GuessPronunciations && len(rhmr.missing) > 0 {
+		guesser := newGuesser(pronunciationMap)
+		for word := range rhmr.missing {
+			pronunciation := guesser.guess(word)
+			if pronunciation != nil {
+				rhmr.rhymes[word] = []*Rhyme{&Rhyme{Word: word, Pronunciation: pronunciation, Guessed: true}}
+			}
+		}
+	}
+
+	rhmr.lines = corpus.Lines
+
+	rhmr.index = newRhymeIndex()
+	for _, rhymes := range rhmr.rhymes {
+		for _, rhyme := range rhymes {
+			rhmr.index.add(rhyme)
+		}
+	}
+
 	return rhmr, nil
 }
 
-// Pronunciations provides the pronunciation of a word. Returns empty string for
-// unknown words. A single word can have multiple pronunciations. Each
-// pronunciation is represented by a string slice of phonemes.
+// Pronunciations provides the pronunciation of a word - any word of the corpus
+// or the pronunciation dictionary. Returns empty string for unknown words. A
+// single word can have multiple pronunciations. Each pronunciation is
+// represented by a string slice of phonemes.
+//
+// Corpus lines are split at hyphens, so a hyphenated word like "bat-faced" is
+// never in the corpus. Its pronunciations are derived from its parts instead.
 func (r *Rhymer) Pronunciations(word string) [][]string {
 	rhymes, ok := r.rhymes[strings.ToLower(word)]
 	if ok {
@@ -112,32 +222,101 @@ func (r *Rhymer) Pronunciations(word string) [][]string {
 		return pronunciations
 	}
 
+	pronunciations, ok := r.dictionary[strings.ToLower(word)]
+	if ok {
+		return pronunciations
+	}
+
+	if strings.Contains(word, "-") {
+		return deriveCompound(strings.ToLower(word), r.Pronunciations)
+	}
+
 	return nil
 }
 
 // Rhymes returns a list of Rhymes that match the word, ordered by strength of
 // the rhyme (number of rhyming syllables).
+//
+// Words that rhyme at all (with a strength of at least 1) are found with an
+// index, in time proportional to the number of rhymes. A _minStrength_ of 0 or
+// less returns every word in the corpus, so it checks them all.
 func (r *Rhymer) Rhymes(word string, pronunciation []string, minStrength int) []*Rhyme {
-	// TODO - efficiant algorithm for finding rhymes
+	var actualRhymes []*Rhyme
+	if minStrength >= 1 {
+		actualRhymes = r.index.rhymes(word, pronunciation, minStrength)
+	} else {
+		actualRhymes = scanRhymes(r.rhymes, word, pronunciation, minStrength)
+	}
+
+	sort.Sort(byStrengthDesc(actualRhymes))
+
+	return actualRhymes
+}
+
+// DictionaryRhymes returns a list of Rhymes that match the word like Rhymes,
+// but from every word of the pronunciation dictionary rather than just the
+// corpus. Corpus words missing from the dictionary are included too, with the
+// pronunciations derived or guessed for them.
+func (r *Rhymer) DictionaryRhymes(word string, pronunciation []string, minStrength int) []*Rhyme {
+	var actualRhymes []*Rhyme
+	if minStrength >= 1 {
+		if r.dictionaryIndex == nil {
+			r.dictionaryIndex = newRhymeIndex()
+			for _, rhymes := range r.dictionaryRhymes() {
+				for _, rhyme := range rhymes {
+					r.dictionaryIndex.add(rhyme)
+				}
+			}
+		}
+		actualRhymes = r.dictionaryIndex.rhymes(word, pronunciation, minStrength)
+	} else {
+		actualRhymes = scanRhymes(r.dictionaryRhymes(), word, pronunciation, minStrength)
+	}
+
+	sort.Sort(byStrengthDesc(actualRhymes))
+
+	return actualRhymes
+}
+
+// dictionaryRhymes returns a Rhyme for every pronunciation of every word of the
+// pronunciation dictionary, and of the corpus words missing from it
+func (r *Rhymer) dictionaryRhymes() map[string][]*Rhyme {
+	all := map[string][]*Rhyme{}
+	for word, pronunciations := range r.dictionary {
+		for _, pronunciation := range pronunciations {
+			all[word] = append(all[word], &Rhyme{Word: word, Pronunciation: pronunciation})
+		}
+	}
+
+	for word, rhymes := range r.rhymes {
+		if _, ok := r.dictionary[word]; !ok {
+			all[word] = rhymes
+		}
+	}
 
+	return all
+}
+
+// scanRhymes returns the Rhymes that match the word, unsorted, by comparing the
+// pronunciation with every pronunciation of every word in rhymes
+func scanRhymes(rhymes map[string][]*Rhyme, word string, pronunciation []string, minStrength int) []*Rhyme {
 	actualRhymes := []*Rhyme{}
 
-	for _, rhymeList := range r.rhymes {
+	for _, rhymeList := range rhymes {
 		for _, rhyme := range rhymeList {
 			strength := rhymeStrength(word, rhyme.Word, pronunciation, rhyme.Pronunciation)
 			if strength >= minStrength {
-				actualRhymes = append(actualRhymes, &Rhyme{Word: rhyme.Word, Pronunciation: rhyme.Pronunciation, Strength: strength})
+				score, kind := RhymeScore(pronunciation, rhyme.Pronunciation)
+				actualRhymes = append(actualRhymes, &Rhyme{Word: rhyme.Word, Pronunciation: rhyme.Pronunciation, Strength: strength, Guessed: rhyme.Guessed, Derived: rhyme.Derived, Kind: kind, Score: score})
 			}
 		}
 	}
 
-	sort.Sort(byStrengthDesc(actualRhymes))
-
 	return actualRhymes
 }
 
 // UnknownPronunciations returns all the words from the corpus that have no
-// known pronunciation.
+// known pronunciation - including any whose pronunciation was guessed.
 func (r *Rhymer) UnknownPronunciations() []string {
 	missing := []string{}
 	for m := range r.missing {
@@ -148,26 +327,99 @@ func (r *Rhymer) UnknownPronunciations() []string {
 	return missing
 }
 
-func getPronunciationFromDictionary(line string) (string, []string) {
-	pieces := strings.Split(line, " ")
+// ResolvedPronunciations returns all the words from the corpus missing from the
+// pronunciation dictionary that an extra dictionary gives a pronunciation.
+func (r *Rhymer) ResolvedPronunciations() []string {
+	resolved := []string{}
+	for word := range r.resolved {
+		resolved = append(resolved, word)
+	}
 
-	if len(pieces) < 3 {
-		return "", nil
+	sort.Strings(resolved)
+	return resolved
+}
+
+// Warnings returns the problems found reading the pronunciation dictionaries
+// that didn't stop them loading, like repeated pronunciations, each with the
+// file and line number.
+func (r *Rhymer) Warnings() []string {
+	return r.warnings
+}
+
+// DerivedPronunciations returns all the words from the corpus missing from the
+// pronunciation dictionary whose pronunciation was put together from the
+// pronunciations of their parts, like "bonedigger" from "bone" and "digger".
+func (r *Rhymer) DerivedPronunciations() []string {
+	derived := []string{}
+	for word := range r.derived {
+		derived = append(derived, word)
 	}
 
-	word := strings.ToLower(pieces[0])
-	pronunciation := pieces[2:]
+	sort.Strings(derived)
+	return derived
+}
+
+// applyExtraDictionary changes the pronunciations in pronunciationMap with the
+// lines of an extra dictionary, in the same format as the pronunciation
+// dictionary. The first line for a word replaces all of the word's
+// pronunciations, and any more lines for the word add to them. A line starting
+// with "-" deletes instead: "-WORD" deletes all of the word's pronunciations,
+// and "-WORD  W ER1 D" deletes just that one, keeping the rest. Returns any
+// warnings from reading the dictionary.
+func applyExtraDictionary(pronunciationMap map[string][][]string, extraDictionaryFilepath string) ([]string, error) {
+	entries, warnings, err := readDictionary(extraDictionaryFilepath, true)
+	if err != nil {
+		return nil, fmt.Errorf("error loading extra pronunciation dictionary: %w", err)
+	}
 
-	leftParenIndex := strings.Index(word, "(")
-	if leftParenIndex > 0 {
-		word = word[:leftParenIndex]
+	// words already changed by this dictionary, which are added to rather than
+	// replaced
+	changed := map[string]bool{}
+	for _, entry := range entries {
+		word := entry.word
+		if entry.remove && entry.pronunciation == nil {
+			delete(pronunciationMap, word)
+		} else if entry.remove {
+			kept := [][]string{}
+			for _, existing := range pronunciationMap[word] {
+				if strings.Join(existing, " ") != strings.Join(entry.pronunciation, " ") {
+					kept = append(kept, existing)
+				}
+			}
+			if len(kept) == 0 {
+				delete(pronunciationMap, word)
+			} else {
+				pronunciationMap[word] = kept
+			}
+		} else if changed[word] {
+			pronunciationMap[word] = append(pronunciationMap[word], entry.pronunciation)
+		} else {
+			pronunciationMap[word] = [][]string{entry.pronunciation}
+		}
+		changed[word] = true
 	}
 
-	return word, pronunciation
+	return warnings, nil
+}
+
+// Tokenize splits a line into words by all non letter/numbers (excluding
+// apostrophes). This is how corpus lines are split when loading a Rhymer, so
+// each resulting word can be looked up directly.
+func Tokenize(line string) []string {
+	return strings.FieldsFunc(line, func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsNumber(c) && c != '\''
+	})
+}
+
+// RhymeStrength is the strength of the rhyme between two words with the given
+// pronunciations (roughly the number of rhyming syllables, as for Rhymes), or -1
+// if they are the same word or sound exactly the same
+func RhymeStrength(word1, word2 string, pronunciation1, pronunciation2 []string) int {
+	return rhymeStrength(word1, word2, pronunciation1, pronunciation2)
 }
 
 func rhymeStrength(word1, word2 string, pronunciation1, pronunciation2 []string) int {
-	if strings.ToLower(word1) == strings.ToLower(word2) || reflect.DeepEqual(normalizeEmphasis(pronunciation1), normalizeEmphasis(pronunciation2)) {
+	if strings.ToLower(word1) == strings.ToLower(word2) || strings.Join(normalizeEmphasis(pronunciation1), " ") == strings.Join(normalizeEmphasis(pronunciation2), " ") {
 		return -1
 	}
 
@@ -12,7 +12,7 @@ import (
 func Test_Load(t *testing.T) {
 	cor, _, err := corpus.Load("../corpus/test_corpus.json", "")
 	if err != nil {
-		t.Errorf("Error loading corpus: %w", err)
+		t.Errorf("Error loading corpus: %v", err)
 	}
 	if cor == nil {
 		t.Errorf("Corpus is nil")
@@ -20,11 +20,24 @@ func Test_Load(t *testing.T) {
 
 	rhmr, err := Load("test_dictionary.txt", cor)
 	if err != nil {
-		t.Errorf("Error loading pronunciation dictionary: %w", err)
+		t.Errorf("Error loading pronunciation dictionary: %v", err)
 	}
 	if rhmr == nil {
 		t.Errorf("rhymer is nil")
 	}
+
+	_, err = Load("test_invalid_dictionary.txt", cor)
+	if err == nil {
+		t.Errorf("expected error loading invalid pronunciation dictionary")
+	}
+
+	rhmr, err = Load("test_variant_dictionary.txt", cor)
+	if err != nil {
+		t.Errorf("Error loading pronunciation dictionary: %v", err)
+	}
+	if len(rhmr.Warnings()) != 1 {
+		t.Errorf("expected 1 warning, got %v", rhmr.Warnings())
+	}
 }
 
 func Test_rhymer_Pronunciations(t *testing.T) {
@@ -306,46 +319,6 @@ func Test_rhymer_UnknownPronunciations(t *testing.T) {
 	}
 }
 
-// less important ()
-func Test_getPronunciationFromDictionary(t *testing.T) {
-	line := "WORD  PHONEME1 PHONEME2"
-	expectedWord := "word"
-	expectedPhonemes := []string{"PHONEME1", "PHONEME2"}
-	word, phonemes := getPronunciationFromDictionary(line)
-	if word != expectedWord {
-		t.Errorf("expected word \"%s\", got \"%s\"", expectedWord, word)
-	}
-	if !reflect.DeepEqual(expectedPhonemes, phonemes) {
-		t.Logf("expected: %v\n", expectedPhonemes)
-		t.Logf("actual: %v\n", phonemes)
-		t.Errorf("unexpected phonemes")
-	}
-
-	line = "WORD(1)  PHONEME1 PHONEME2"
-	expectedWord = "word"
-	expectedPhonemes = []string{"PHONEME1", "PHONEME2"}
-	word, phonemes = getPronunciationFromDictionary(line)
-	if word != expectedWord {
-		t.Errorf("expected word \"%s\", got \"%s\"", expectedWord, word)
-	}
-	if !reflect.DeepEqual(expectedPhonemes, phonemes) {
-		t.Logf("expected: %v\n", expectedPhonemes)
-		t.Logf("actual: %v\n", phonemes)
-		t.Errorf("unexpected phonemes")
-	}
-
-	line = "INVALID"
-	word, phonemes = getPronunciationFromDictionary(line)
-	if word != "" {
-		t.Errorf("expected empty word, got \"%s\"", word)
-	}
-	if len(phonemes) > 0 {
-		t.Logf("expected: %v\n", []string{})
-		t.Logf("actual: %v\n", phonemes)
-		t.Errorf("unexpected phonemes")
-	}
-}
-
 func Test_rhymeStrength(t *testing.T) {
 
 	// strength 4
@@ -711,3 +684,74 @@ func Test_byStrengthDesc(t *testing.T) {
 		t.Errorf("unexpected ordering")
 	}
 }
+
+func Test_LoadWithOptions_ExtraDictionaries(t *testing.T) {
+	cor, _, _ := corpus.Load("../corpus/test_corpus.json", "")
+	rhmr, err := LoadWithOptions("test_dictionary.txt", cor, Options{ExtraDictionaries: []string{"test_extra_dictionary.txt"}})
+	if err != nil {
+		t.Fatalf("Error loading pronunciation dictionary: %v", err)
+	}
+
+	expected := map[string][][]string{
+		"beerbelly": [][]string{[]string{"B", "IH1", "R", "B", "EH2", "L", "IY0"}},
+		"al":        [][]string{[]string{"AA1", "L"}},
+		"am":        [][]string{[]string{"AE1", "M"}},
+		"betty":     [][]string{},
+		"the":       [][]string{[]string{"DH", "AH0"}, []string{"DH", "IY1"}},
+		"be":        [][]string{[]string{"B", "IY1"}, []string{"B", "IY0"}},
+	}
+	for word, pronunciations := range expected {
+		if !reflect.DeepEqual(pronunciations, rhmr.Pronunciations(word)) {
+			t.Errorf("expected %v for \"%s\", got %v", pronunciations, word, rhmr.Pronunciations(word))
+		}
+	}
+
+	if !reflect.DeepEqual([]string{"beerbelly", "bonedigger"}, rhmr.ResolvedPronunciations()) {
+		t.Errorf("expected beerbelly and bonedigger to be resolved, got %v", rhmr.ResolvedPronunciations())
+	}
+	if !reflect.DeepEqual([]string{"betty", "currency", "roly", "scatterings"}, rhmr.UnknownPronunciations()) {
+		t.Errorf("unexpected unknown words %v", rhmr.UnknownPronunciations())
+	}
+
+	_, err = LoadWithOptions("test_dictionary.txt", cor, Options{ExtraDictionaries: []string{"missing.txt"}})
+	if err == nil {
+		t.Errorf("expected error loading missing extra dictionary")
+	}
+}
+
+func Test_rhymer_DictionaryRhymes(t *testing.T) {
+	// "span" and "can" are in the dictionary, but not the corpus
+	cor := &corpus.Corpus{Lines: []string{"A man."}}
+	rhmr, err := Load("test_dictionary.txt", cor)
+	if err != nil {
+		t.Fatalf("Error loading pronunciation dictionary: %v", err)
+	}
+
+	expected := [][]string{[]string{"S", "P", "AE1", "N"}}
+	if !reflect.DeepEqual(expected, rhmr.Pronunciations("span")) {
+		t.Errorf("expected %v for \"span\", got %v", expected, rhmr.Pronunciations("span"))
+	}
+
+	words := func(rhymes []*Rhyme) []string {
+		found := []string{}
+		for _, rhyme := range rhymes {
+			found = append(found, rhyme.Word)
+		}
+		return found
+	}
+
+	pronunciation := rhmr.Pronunciations("span")[0]
+	if !reflect.DeepEqual([]string{"man"}, words(rhmr.Rhymes("span", pronunciation, 1))) {
+		t.Errorf("expected only corpus rhymes for \"span\", got %v", words(rhmr.Rhymes("span", pronunciation, 1)))
+	}
+
+	dictionaryWords := words(rhmr.DictionaryRhymes("span", pronunciation, 1))
+	if !reflect.DeepEqual([]string{"can", "man"}, dictionaryWords) {
+		t.Errorf("expected dictionary rhymes [can man] for \"span\", got %v", dictionaryWords)
+	}
+
+	all := rhmr.DictionaryRhymes("span", pronunciation, 0)
+	if len(all) <= len(dictionaryWords) || all[0].Strength != 1 {
+		t.Errorf("expected every dictionary word, strongest first, got %v", words(all))
+	}
+}
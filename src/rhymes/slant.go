@@ -0,0 +1,327 @@
+package rhymes
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// RhymeKind is how two words rhyme
+type RhymeKind int
+
+const (
+	// NoRhyme is two words that don't rhyme, even a little
+	NoRhyme RhymeKind = iota
+
+	// Perfect is two words that sound the same from their last stressed vowel,
+	// like "along" and "song"
+	Perfect
+
+	// Assonant is two words with the same vowels from their last stressed vowel,
+	// but different consonants, like "gone" and "along"
+	Assonant
+
+	// Consonant is two words with the same consonants after their last stressed
+	// vowel, but different vowels, like "bat" and "bit"
+	Consonant
+
+	// Slant is two words with similar but different vowels and consonants from
+	// their last stressed vowel, like "bit" and "bed"
+	Slant
+)
+
+func (k RhymeKind) String() string {
+	switch k {
+	case Perfect:
+		return "perfect"
+	case Assonant:
+		return "assonant"
+	case Consonant:
+		return "consonant"
+	case Slant:
+		return "slant"
+	}
+	return "none"
+}
+
+// vowel features - height (1 high to 0 low), backness (0 front to 1 back),
+// rounding, and whether the vowel glides into another (a diphthong) or is
+// r-colored
+type vowelFeatures struct {
+	height, backness float64
+	rounded, glide   bool
+	rhotic           bool
+}
+
+var vowels = map[string]vowelFeatures{
+	"IY": {height: 1, backness: 0},
+	"IH": {height: 0.8, backness: 0.1},
+	"EY": {height: 0.6, backness: 0, glide: true},
+	"EH": {height: 0.5, backness: 0},
+	"AE": {height: 0.2, backness: 0},
+	"AA": {height: 0, backness: 1},
+	"AO": {height: 0.3, backness: 1, rounded: true},
+	"OW": {height: 0.6, backness: 1, rounded: true, glide: true},
+	"UH": {height: 0.8, backness: 0.9, rounded: true},
+	"UW": {height: 1, backness: 1, rounded: true},
+	"AH": {height: 0.4, backness: 0.5},
+	"ER": {height: 0.5, backness: 0.5, rhotic: true},
+	"AY": {height: 0.1, backness: 0.5, glide: true},
+	"AW": {height: 0.1, backness: 0.5, glide: true},
+	"OY": {height: 0.4, backness: 1, rounded: true, glide: true},
+}
+
+// places of articulation, from the front of the mouth to the back
+const (
+	bilabial = iota
+	labiodental
+	dental
+	alveolar
+	postalveolar
+	palatal
+	velar
+	glottal
+)
+
+// manners of articulation
+const (
+	stop = iota
+	affricate
+	fricative
+	nasal
+	liquid
+	glide
+)
+
+// consonant features - where (place) and how (manner) the consonant is made,
+// and whether it's voiced
+type consonantFeatures struct {
+	place, manner int
+	voiced        bool
+}
+
+var consonants = map[string]consonantFeatures{
+	"P":  {bilabial, stop, false},
+	"B":  {bilabial, stop, true},
+	"T":  {alveolar, stop, false},
+	"D":  {alveolar, stop, true},
+	"K":  {velar, stop, false},
+	"G":  {velar, stop, true},
+	"CH": {postalveolar, affricate, false},
+	"JH": {postalveolar, affricate, true},
+	"F":  {labiodental, fricative, false},
+	"V":  {labiodental, fricative, true},
+	"TH": {dental, fricative, false},
+	"DH": {dental, fricative, true},
+	"S":  {alveolar, fricative, false},
+	"Z":  {alveolar, fricative, true},
+	"SH": {postalveolar, fricative, false},
+	"ZH": {postalveolar, fricative, true},
+	"HH": {glottal, fricative, false},
+	"M":  {bilabial, nasal, true},
+	"N":  {alveolar, nasal, true},
+	"NG": {velar, nasal, true},
+	"L":  {alveolar, liquid, true},
+	"R":  {postalveolar, liquid, true},
+	"W":  {bilabial, glide, true},
+	"Y":  {palatal, glide, true},
+}
+
+// SlantRhymes returns the corpus words that rhyme with the word at least a
+// little - perfect, assonant, consonant and slant rhymes - with a Score of at
+// least _minScore_ (see RhymeScore), ordered by descending score.
+//
+// Unlike Rhymes, it compares the pronunciation with every pronunciation in the
+// corpus.
+func (r *Rhymer) SlantRhymes(word string, pronunciation []string, minScore float64) []*Rhyme {
+	sound := strings.Join(normalizeEmphasis(pronunciation), " ")
+
+	found := []*Rhyme{}
+	for _, rhymeList := range r.rhymes {
+		for _, rhyme := range rhymeList {
+			if strings.ToLower(rhyme.Word) == strings.ToLower(word) || strings.Join(normalizeEmphasis(rhyme.Pronunciation), " ") == sound {
+				continue
+			}
+
+			score, kind := RhymeScore(pronunciation, rhyme.Pronunciation)
+			if kind == NoRhyme || score < minScore {
+				continue
+			}
+
+			found = append(found, &Rhyme{
+				Word:          rhyme.Word,
+				Pronunciation: rhyme.Pronunciation,
+				Strength:      rhymeStrength(word, rhyme.Word, pronunciation, rhyme.Pronunciation),
+				Guessed:       rhyme.Guessed,
+				Derived:       rhyme.Derived,
+				Kind:          kind,
+				Score:         score,
+			})
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].Score == found[j].Score {
+			return found[i].Word < found[j].Word
+		}
+		return found[i].Score > found[j].Score
+	})
+
+	return found
+}
+
+// RhymeScore scores how well two pronunciations rhyme, from 0 (not at all) to
+// 1 (a perfect rhyme), and says what kind of rhyme it is.
+//
+// Only the sounds from the last stressed vowel of each pronunciation count.
+// Each of those syllables is scored by how alike its vowels are (in height,
+// backness and rounding) and how alike the consonants after it are (in place,
+// manner and voicing), with the vowels and consonants counting equally. A
+// pronunciation with more of those syllables than the other scores lower.
+func RhymeScore(pronunciation1, pronunciation2 []string) (float64, RhymeKind) {
+	tail1 := stressedTail(pronunciation1)
+	tail2 := stressedTail(pronunciation2)
+	if len(tail1) == 0 || len(tail2) == 0 {
+		return 0, NoRhyme
+	}
+
+	if fmt.Sprint(tail1) == fmt.Sprint(tail2) {
+		return 1, Perfect
+	}
+
+	shorter := len(tail1)
+	if len(tail2) < shorter {
+		shorter = len(tail2)
+	}
+	longer := len(tail1) + len(tail2) - shorter
+
+	vowelScore := 0.0
+	consonantScore := 0.0
+	for i := 1; i <= shorter; i++ {
+		syllable1 := tail1[len(tail1)-i]
+		syllable2 := tail2[len(tail2)-i]
+		vowelScore += vowelSimilarity(syllable1[0][:len(syllable1[0])-1], syllable2[0][:len(syllable2[0])-1])
+		consonantScore += codaSimilarity(syllable1[1:], syllable2[1:])
+	}
+	vowelScore /= float64(shorter)
+	consonantScore /= float64(shorter)
+
+	score := (vowelScore + consonantScore) / 2 * float64(shorter) / float64(longer)
+	if score == 0 {
+		return 0, NoRhyme
+	}
+
+	kind := Slant
+	if len(tail1) == len(tail2) && vowelScore == 1 {
+		kind = Assonant
+	} else if len(tail1) == len(tail2) && consonantScore == 1 {
+		kind = Consonant
+	}
+
+	return score, kind
+}
+
+// stressedTail returns the syllables of a pronunciation from its last stressed
+// vowel, or just the last syllable if none are stressed. Each syllable is a
+// vowel followed by the consonants after it, like the rhyme syllables, but as
+// separate phonemes with the stress made the same.
+func stressedTail(pronunciation []string) [][]string {
+	syllables := [][]string{}
+	for _, phoneme := range normalizeEmphasis(pronunciation) {
+		if isVowelPhoneme(phoneme) {
+			syllables = append(syllables, []string{phoneme})
+		} else if len(syllables) > 0 {
+			syllables[len(syllables)-1] = append(syllables[len(syllables)-1], phoneme)
+		}
+	}
+
+	for i := len(syllables) - 1; i >= 0; i-- {
+		if strings.HasSuffix(syllables[i][0], "1") {
+			return syllables[i:]
+		}
+	}
+
+	if len(syllables) == 0 {
+		return nil
+	}
+	return syllables[len(syllables)-1:]
+}
+
+func vowelSimilarity(vowel1, vowel2 string) float64 {
+	if vowel1 == vowel2 {
+		return 1
+	}
+
+	features1, ok1 := vowels[vowel1]
+	features2, ok2 := vowels[vowel2]
+	if !ok1 || !ok2 {
+		return 0
+	}
+
+	distance := math.Abs(features1.height-features2.height) + math.Abs(features1.backness-features2.backness)
+	if features1.rounded != features2.rounded {
+		distance += 0.25
+	}
+	if features1.glide != features2.glide {
+		distance += 0.25
+	}
+	if features1.rhotic != features2.rhotic {
+		distance += 0.5
+	}
+
+	return math.Max(0, 1-distance/2)
+}
+
+func consonantSimilarity(consonant1, consonant2 string) float64 {
+	if consonant1 == consonant2 {
+		return 1
+	}
+
+	features1, ok1 := consonants[consonant1]
+	features2, ok2 := consonants[consonant2]
+	if !ok1 || !ok2 {
+		return 0
+	}
+
+	similarity := 1.0
+	similarity -= 0.4 * math.Abs(float64(features1.place-features2.place)) / glottal
+	if features1.manner != features2.manner {
+		similarity -= 0.4
+	}
+	if features1.voiced != features2.voiced {
+		similarity -= 0.2
+	}
+
+	return similarity
+}
+
+// codaSimilarity scores how alike two runs of consonants are, from 0 to 1, by
+// the cheapest way to turn one into the other - adding or removing a consonant
+// costs 1, and swapping one for another costs how different they are
+func codaSimilarity(coda1, coda2 []string) float64 {
+	if len(coda1) == 0 && len(coda2) == 0 {
+		return 1
+	}
+
+	cost := make([][]float64, len(coda1)+1)
+	for i := range cost {
+		cost[i] = make([]float64, len(coda2)+1)
+		cost[i][0] = float64(i)
+	}
+	for j := range cost[0] {
+		cost[0][j] = float64(j)
+	}
+
+	for i := 1; i <= len(coda1); i++ {
+		for j := 1; j <= len(coda2); j++ {
+			cost[i][j] = math.Min(
+				math.Min(cost[i-1][j]+1, cost[i][j-1]+1),
+				cost[i-1][j-1]+1-consonantSimilarity(coda1[i-1], coda2[j-1]),
+			)
+		}
+	}
+
+	longer := math.Max(float64(len(coda1)), float64(len(coda2)))
+	return 1 - cost[len(coda1)][len(coda2)]/longer
+}
@@ -0,0 +1,87 @@
+package rhymes
+
+import (
+	"testing"
+
+	"github.com/verkestk/goetry/src/corpus"
+)
+
+func Test_RhymeScore(t *testing.T) {
+	pronunciations := map[string][]string{
+		"along":  []string{"AH0", "L", "AO1", "NG"},
+		"song":   []string{"S", "AO1", "NG"},
+		"gone":   []string{"G", "AO1", "N"},
+		"bat":    []string{"B", "AE1", "T"},
+		"bit":    []string{"B", "IH1", "T"},
+		"bed":    []string{"B", "EH1", "D"},
+		"middle": []string{"M", "IH1", "D", "AH0", "L"},
+		"little": []string{"L", "IH1", "T", "AH0", "L"},
+		"hmm":    []string{"HH", "M"},
+	}
+
+	expected := []struct {
+		word1, word2 string
+		kind         RhymeKind
+	}{
+		{"along", "song", Perfect},
+		{"gone", "along", Assonant},
+		{"middle", "little", Assonant},
+		{"bat", "bit", Consonant},
+		{"bit", "bed", Slant},
+		{"bit", "middle", Slant},
+		{"hmm", "song", NoRhyme},
+	}
+	for _, e := range expected {
+		score, kind := RhymeScore(pronunciations[e.word1], pronunciations[e.word2])
+		if kind != e.kind {
+			t.Errorf("expected %s rhyme for \"%s\" and \"%s\", got %s (%f)", e.kind, e.word1, e.word2, kind, score)
+		}
+		if (kind == Perfect) != (score == 1) || (kind == NoRhyme) != (score == 0) {
+			t.Errorf("unexpected score %f for %s rhyme \"%s\" and \"%s\"", score, kind, e.word1, e.word2)
+		}
+	}
+
+	// closer sounds score higher
+	gone, _ := RhymeScore(pronunciations["gone"], pronunciations["along"])
+	bat, _ := RhymeScore(pronunciations["gone"], pronunciations["bat"])
+	bed, _ := RhymeScore(pronunciations["bit"], pronunciations["bed"])
+	middle, _ := RhymeScore(pronunciations["bit"], pronunciations["middle"])
+	if gone <= bat || bed <= middle {
+		t.Errorf("unexpected scores: gone/along %f, gone/bat %f, bit/bed %f, bit/middle %f", gone, bat, bed, middle)
+	}
+}
+
+func Test_rhymer_SlantRhymes(t *testing.T) {
+	cor, _, _ := corpus.Load("../corpus/test_corpus.json", "")
+	rhmr, _ := Load("test_dictionary.txt", cor)
+
+	word := "gone"
+	rhymes := rhmr.SlantRhymes(word, rhmr.Pronunciations(word)[0], 0.75)
+	if len(rhymes) == 0 {
+		t.Fatalf("expected slant rhymes for \"%s\"", word)
+	}
+
+	kinds := map[string]RhymeKind{}
+	for i, rhyme := range rhymes {
+		kinds[rhyme.Word] = rhyme.Kind
+		if rhyme.Score < 0.75 || rhyme.Kind == NoRhyme {
+			t.Errorf("unexpected rhyme \"%s\" with score %f", rhyme.Word, rhyme.Score)
+		}
+		if i > 0 && rhymes[i-1].Score < rhyme.Score {
+			t.Errorf("expected rhymes in descending order of score")
+		}
+		if rhyme.Word == word {
+			t.Errorf("expected \"%s\" not to rhyme with itself", word)
+		}
+	}
+	if kinds["along"] != Assonant {
+		t.Errorf("expected \"along\" to be an assonant rhyme for \"%s\", got %s", word, kinds["along"])
+	}
+
+	// perfect rhymes have their kind too
+	for _, rhyme := range rhmr.Rhymes("alley", rhmr.Pronunciations("alley")[0], 2) {
+		if rhyme.Kind == NoRhyme || rhyme.Score == 0 {
+			t.Errorf("expected a kind and a score for rhyme \"%s\"", rhyme.Word)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package rhymes
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Stresses returns every stress pattern a word can have across all of its
+// pronunciations, in ascending order. A stress pattern has one digit per
+// syllable, taken from the dictionary: "1" for primary stress, "2" for
+// secondary stress, and "0" for no stress. Returns nil for unknown words.
+func (r *Rhymer) Stresses(word string) []string {
+	pronunciations := r.Pronunciations(word)
+	if len(pronunciations) == 0 {
+		return nil
+	}
+
+	patterns := map[string]bool{}
+	for _, pronunciation := range pronunciations {
+		patterns[stressPattern(pronunciation)] = true
+	}
+
+	sorted := []string{}
+	for pattern := range patterns {
+		sorted = append(sorted, pattern)
+	}
+
+	sort.Strings(sorted)
+	return sorted
+}
+
+// LineStresses returns the possible stress patterns of each word in a line of
+// text, in the order the words are returned by Tokenize. Concatenating one
+// pattern per word gives a stress pattern for the whole line. Returns nil if any
+// word in the line has no known pronunciation.
+func (r *Rhymer) LineStresses(line string) [][]string {
+	stresses := [][]string{}
+	for _, word := range Tokenize(line) {
+		patterns := r.Stresses(word)
+		if len(patterns) == 0 {
+			return nil
+		}
+		stresses = append(stresses, patterns)
+	}
+
+	return stresses
+}
+
+// stressPattern collects the stress digit from each vowel phoneme of a
+// pronunciation.
+func stressPattern(pronunciation []string) string {
+	var pattern strings.Builder
+	for _, phoneme := range pronunciation {
+		if isVowelPhoneme(phoneme) {
+			lastRune, _ := utf8.DecodeLastRuneInString(phoneme)
+			pattern.WriteRune(lastRune)
+		}
+	}
+
+	return pattern.String()
+}
@@ -0,0 +1,60 @@
+package rhymes
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/verkestk/goetry/src/corpus"
+)
+
+func Test_rhymer_Stresses(t *testing.T) {
+	cor, _, _ := corpus.Load("../corpus/test_corpus.json", "")
+	rhmr, _ := Load("test_dictionary.txt", cor)
+
+	words := map[string][]string{
+		"beerbelly":   nil,
+		"the":         []string{"0", "1"},
+		"family":      []string{"10", "100"},
+		"Hallelujah":  []string{"2010"},
+		"opportunity": []string{"20100"},
+	}
+
+	for word, expected := range words {
+		actual := rhmr.Stresses(word)
+		if !reflect.DeepEqual(expected, actual) {
+			t.Logf("expected: %v\n", expected)
+			t.Logf("actual: %v\n", actual)
+			t.Errorf("unexpected stresses for \"%s\"", word)
+		}
+	}
+}
+
+func Test_rhymer_LineStresses(t *testing.T) {
+	cor, _, _ := corpus.Load("../corpus/test_corpus.json", "")
+	rhmr, _ := Load("test_dictionary.txt", cor)
+
+	line := "Where's my wife and family?"
+	expected := [][]string{[]string{"1"}, []string{"1"}, []string{"1"}, []string{"0", "1"}, []string{"10", "100"}}
+	actual := rhmr.LineStresses(line)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Logf("expected: %v\n", expected)
+		t.Logf("actual: %v\n", actual)
+		t.Errorf("unexpected stresses for \"%s\"", line)
+	}
+
+	line = "Mr. Beerbelly"
+	actual = rhmr.LineStresses(line)
+	if actual != nil {
+		t.Logf("actual: %v\n", actual)
+		t.Errorf("expected nil stresses for \"%s\"", line)
+	}
+}
+
+func Test_stressPattern(t *testing.T) {
+	pronunciation := []string{"AE2", "S", "T", "R", "OW0", "F", "AH0", "T", "AA1", "G", "R", "AH0", "F", "IY0"}
+	expected := "200100"
+	actual := stressPattern(pronunciation)
+	if expected != actual {
+		t.Errorf("expected stress pattern \"%s\", got \"%s\"", expected, actual)
+	}
+}
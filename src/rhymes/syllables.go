@@ -0,0 +1,68 @@
+package rhymes
+
+import (
+	"sort"
+)
+
+// Syllables returns every syllable count a word can have across all of its
+// pronunciations, in ascending order. Returns nil for unknown words.
+func (r *Rhymer) Syllables(word string) []int {
+	pronunciations := r.Pronunciations(word)
+	if len(pronunciations) == 0 {
+		return nil
+	}
+
+	counts := map[int]bool{}
+	for _, pronunciation := range pronunciations {
+		counts[countSyllables(pronunciation)] = true
+	}
+
+	return sortedCounts(counts)
+}
+
+// LineSyllables returns every syllable total a line of text can have, given all
+// the combinations of pronunciations of its words, in ascending order. Returns
+// nil if any word in the line has no known pronunciation.
+func (r *Rhymer) LineSyllables(line string) []int {
+	totals := map[int]bool{0: true}
+
+	for _, word := range Tokenize(line) {
+		counts := r.Syllables(word)
+		if len(counts) == 0 {
+			return nil
+		}
+
+		nextTotals := map[int]bool{}
+		for total := range totals {
+			for _, count := range counts {
+				nextTotals[total+count] = true
+			}
+		}
+		totals = nextTotals
+	}
+
+	return sortedCounts(totals)
+}
+
+// countSyllables counts the vowel phonemes in a pronunciation - each vowel is
+// the nucleus of exactly one syllable.
+func countSyllables(pronunciation []string) int {
+	count := 0
+	for _, phoneme := range pronunciation {
+		if isVowelPhoneme(phoneme) {
+			count++
+		}
+	}
+
+	return count
+}
+
+func sortedCounts(counts map[int]bool) []int {
+	sorted := []int{}
+	for count := range counts {
+		sorted = append(sorted, count)
+	}
+
+	sort.Ints(sorted)
+	return sorted
+}
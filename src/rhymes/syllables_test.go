@@ -0,0 +1,71 @@
+package rhymes
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/verkestk/goetry/src/corpus"
+)
+
+func Test_rhymer_Syllables(t *testing.T) {
+	cor, _, _ := corpus.Load("../corpus/test_corpus.json", "")
+	rhmr, _ := Load("test_dictionary.txt", cor)
+
+	words := map[string][]int{
+		"beerbelly":   nil,
+		"a":           []int{1},
+		"family":      []int{2, 3},
+		"FAMILY":      []int{2, 3},
+		"hallelujah":  []int{4},
+		"opportunity": []int{5},
+		"are":         []int{1},
+	}
+
+	for word, expected := range words {
+		actual := rhmr.Syllables(word)
+		if !reflect.DeepEqual(expected, actual) {
+			t.Logf("expected: %v\n", expected)
+			t.Logf("actual: %v\n", actual)
+			t.Errorf("unexpected syllable counts for \"%s\"", word)
+		}
+	}
+}
+
+func Test_rhymer_LineSyllables(t *testing.T) {
+	cor, _, _ := corpus.Load("../corpus/test_corpus.json", "")
+	rhmr, _ := Load("test_dictionary.txt", cor)
+
+	lines := map[string][]int{
+		"Call me.":                         []int{2},
+		"Where's my wife and family?":      []int{6, 7},
+		"Mr. Beerbelly, Beerbelly":         nil,
+		"a photo-opportunity":              []int{8},
+		"":                                 []int{0},
+		"Why am I soft in the middle, now": []int{9, 10},
+	}
+
+	for line, expected := range lines {
+		actual := rhmr.LineSyllables(line)
+		if !reflect.DeepEqual(expected, actual) {
+			t.Logf("expected: %v\n", expected)
+			t.Logf("actual: %v\n", actual)
+			t.Errorf("unexpected syllable counts for \"%s\"", line)
+		}
+	}
+}
+
+func Test_countSyllables(t *testing.T) {
+	pronunciation := []string{"AE2", "S", "T", "R", "OW0", "F", "AH0", "T", "AA1", "G", "R", "AH0", "F", "IY0"}
+	expected := 6
+	actual := countSyllables(pronunciation)
+	if expected != actual {
+		t.Errorf("expected %d syllables, got %d", expected, actual)
+	}
+
+	pronunciation = []string{"HH", "M"}
+	expected = 0
+	actual = countSyllables(pronunciation)
+	if expected != actual {
+		t.Errorf("expected %d syllables, got %d", expected, actual)
+	}
+}
@@ -0,0 +1,413 @@
+package scheme
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/verkestk/goetry/src/form"
+	"github.com/verkestk/goetry/src/meter"
+	"github.com/verkestk/goetry/src/rhymes"
+)
+
+// the most times the pronunciations of the end words are reconsidered
+const maxPronunciationRounds = 10
+
+// the lowest score (see GuessForm) of a form that a poem can be said to follow
+const minFormScore = 0.75
+
+// Line is a line of an analyzed poem
+type Line struct {
+	Text string
+
+	// the last word of the line, lowercased
+	EndWord string
+
+	// the pronunciation of the EndWord that rhymes best with the other end
+	// words, nil if the EndWord has no known pronunciation
+	Pronunciation []string
+
+	// the line in the rhyme scheme - letters naming its rhyme group (A to Z,
+	// then AA, AB, and so on), followed by a number if the line is a refrain,
+	// or "-" if the line doesn't rhyme with any other
+	Rhyme string
+
+	// the standard meter the line fits best, nil if any of its words has no
+	// known pronunciation
+	Scansion *meter.Scansion
+}
+
+// Analysis is how an existing poem rhymes and scans
+type Analysis struct {
+	Stanzas [][]*Line
+}
+
+// Parse splits the text of a poem into stanzas of lines - one line per line,
+// with blank lines between stanzas
+func Parse(text string) [][]string {
+	stanzas := [][]string{}
+	stanza := []string{}
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			stanza = append(stanza, line)
+			continue
+		}
+
+		if len(stanza) > 0 {
+			stanzas = append(stanzas, stanza)
+			stanza = []string{}
+		}
+	}
+
+	if len(stanza) > 0 {
+		stanzas = append(stanzas, stanza)
+	}
+
+	return stanzas
+}
+
+// Detect works out the rhyme scheme and the meter of a poem. Two lines rhyme if
+// their end words rhyme at at least _strength_, or are the same word. When an
+// end word has more than one pronunciation, the one that rhymes with the most
+// other end words is used.
+//
+// Rhyme groups are lettered in the order they first appear. Lines repeated
+// word for word are refrains, numbered within their group like the rhyme
+// schemes of forms, e.g. "A1bA2" for the start of a villanelle.
+func Detect(stanzas [][]string, rhymer *rhymes.Rhymer, strength int) *Analysis {
+	analysis := &Analysis{}
+	lines := []*Line{}
+	for _, stanza := range stanzas {
+		analyzed := []*Line{}
+		for _, text := range stanza {
+			line := &Line{Text: text}
+			words := rhymes.Tokenize(text)
+			if len(words) > 0 {
+				line.EndWord = strings.ToLower(words[len(words)-1])
+			}
+
+			wordStresses := rhymer.LineStresses(text)
+			if len(wordStresses) > 0 {
+				line.Scansion = meter.BestFoot(wordStresses)
+			}
+
+			analyzed = append(analyzed, line)
+			lines = append(lines, line)
+		}
+		analysis.Stanzas = append(analysis.Stanzas, analyzed)
+	}
+
+	pronunciations := make([][][]string, len(lines))
+	for i, line := range lines {
+		pronunciations[i] = rhymer.Pronunciations(line.EndWord)
+	}
+	chosen := choosePronunciations(lines, pronunciations, strength)
+	for i, line := range lines {
+		if len(pronunciations[i]) > 0 {
+			line.Pronunciation = pronunciations[i][chosen[i]]
+		}
+	}
+
+	letterRhymes(lines, strength)
+	numberRefrains(lines)
+
+	return analysis
+}
+
+// Scheme is the rhyme scheme of the poem, with stanzas separated by spaces, e.g.
+// "ABAB CDCD EFEF GG"
+func (a *Analysis) Scheme() string {
+	stanzas := []string{}
+	for _, stanza := range a.Stanzas {
+		var scheme strings.Builder
+		for _, line := range stanza {
+			scheme.WriteString(line.Rhyme)
+		}
+		stanzas = append(stanzas, scheme.String())
+	}
+
+	return strings.Join(stanzas, " ")
+}
+
+// GuessForm finds the form the poem follows best. A form needs the same number
+// of stanzas and lines as the poem, and is scored from 0 to 1 by the share of
+// lines that fit its syllable counts and meter (within its tolerance), averaged
+// with how well the poem agrees with its rhyme scheme (see rhymeAgreement).
+// Returns nil if no form scores at least 0.75. Ties go to the earliest form.
+func (a *Analysis) GuessForm(forms []*form.Form, rhymer *rhymes.Rhymer) (*form.Form, float64) {
+	var best *form.Form
+	bestScore := 0.0
+	for _, f := range forms {
+		formLines, err := f.Lines()
+		if err != nil || !sameShape(a.Stanzas, formLines) {
+			continue
+		}
+
+		lines := []*Line{}
+		expected := []*form.Line{}
+		for i := range a.Stanzas {
+			lines = append(lines, a.Stanzas[i]...)
+			expected = append(expected, formLines[i]...)
+		}
+
+		fitting := 0
+		for i, line := range lines {
+			if fits(line.Text, expected[i], rhymer) {
+				fitting++
+			}
+		}
+		score := float64(fitting) / float64(len(lines))
+
+		if f.Rhyme != "" {
+			score = (score + rhymeAgreement(lines, expected)) / 2
+		}
+
+		if score > bestScore {
+			best = f
+			bestScore = score
+		}
+	}
+
+	if bestScore < minFormScore {
+		return nil, bestScore
+	}
+
+	return best, bestScore
+}
+
+// choosePronunciations picks a pronunciation for the end word of each line,
+// returning its index. Each end word first gets the pronunciation that could
+// rhyme with the most other end words, and then the one that rhymes with the
+// most of the pronunciations chosen for the others, until nothing changes. End
+// words without a pronunciation are left at 0.
+func choosePronunciations(lines []*Line, pronunciations [][][]string, strength int) []int {
+	chosen := make([]int, len(lines))
+
+	rhymeCount := func(i, p int, any bool) int {
+		count := 0
+		for j := range lines {
+			if j == i || len(pronunciations[j]) == 0 {
+				continue
+			}
+			for q := range pronunciations[j] {
+				if (any || q == chosen[j]) && rhymeWith(lines[i].EndWord, lines[j].EndWord, pronunciations[i][p], pronunciations[j][q], strength) {
+					count++
+					break
+				}
+			}
+		}
+		return count
+	}
+
+	for round := 0; round <= maxPronunciationRounds; round++ {
+		changed := false
+		for i := range lines {
+			if len(pronunciations[i]) == 0 {
+				continue
+			}
+
+			best := chosen[i]
+			bestCount := rhymeCount(i, best, round == 0)
+			for p := range pronunciations[i] {
+				count := rhymeCount(i, p, round == 0)
+				if count > bestCount {
+					best = p
+					bestCount = count
+				}
+			}
+
+			if best != chosen[i] {
+				chosen[i] = best
+				changed = true
+			}
+		}
+
+		if round > 0 && !changed {
+			break
+		}
+	}
+
+	return chosen
+}
+
+// letterRhymes sets the rhyme of each line, putting it in the group of the
+// closest line before it that it rhymes with
+func letterRhymes(lines []*Line, strength int) {
+	groups := make([]int, len(lines))
+	sizes := []int{}
+	for i, line := range lines {
+		groups[i] = -1
+		for j := i - 1; j >= 0; j-- {
+			if rhymeWith(line.EndWord, lines[j].EndWord, line.Pronunciation, lines[j].Pronunciation, strength) {
+				groups[i] = groups[j]
+				break
+			}
+		}
+
+		if groups[i] == -1 {
+			groups[i] = len(sizes)
+			sizes = append(sizes, 0)
+		}
+		sizes[groups[i]]++
+	}
+
+	// only groups of more than one line get letters, in order
+	letters := map[int]string{}
+	next := 0
+	for i, line := range lines {
+		letter, ok := letters[groups[i]]
+		if !ok {
+			letter = "-"
+			if sizes[groups[i]] > 1 {
+				letter = groupLetters(next)
+				next++
+			}
+			letters[groups[i]] = letter
+		}
+		line.Rhyme = letter
+	}
+}
+
+// groupLetters names the nth rhyme group - A to Z, then AA, AB, and so on
+func groupLetters(n int) string {
+	letters := string(rune('A' + n%26))
+	for n >= 26 {
+		n = n/26 - 1
+		letters = string(rune('A'+n%26)) + letters
+	}
+
+	return letters
+}
+
+// rhymeGroup is the letters of a line's rhyme, without its refrain number
+func rhymeGroup(rhyme string) string {
+	return strings.TrimRight(rhyme, "0123456789")
+}
+
+// numberRefrains adds a refrain number to the rhyme of each line that is
+// repeated word for word, counting the refrains of each rhyme group from 1
+func numberRefrains(lines []*Line) {
+	counts := map[string]int{}
+	for _, line := range lines {
+		counts[normalize(line.Text)]++
+	}
+
+	numbers := map[string]string{}
+	refrains := map[string]int{}
+	for _, line := range lines {
+		text := normalize(line.Text)
+		if counts[text] < 2 || line.Rhyme == "-" {
+			continue
+		}
+
+		number, ok := numbers[text]
+		if !ok {
+			refrains[line.Rhyme]++
+			number = strconv.Itoa(refrains[line.Rhyme])
+			numbers[text] = number
+		}
+		line.Rhyme += number
+	}
+}
+
+// rhymeWith checks whether two end words rhyme at at least _strength_, or are
+// the same word or sound
+func rhymeWith(word1, word2 string, pronunciation1, pronunciation2 []string, strength int) bool {
+	if word1 != "" && word1 == word2 {
+		return true
+	}
+	if len(pronunciation1) == 0 || len(pronunciation2) == 0 {
+		return false
+	}
+
+	s := rhymes.RhymeStrength(word1, word2, pronunciation1, pronunciation2)
+	return s == -1 || s >= strength
+}
+
+// normalize is the words of a line, lowercased, so that repeated lines match
+// despite their punctuation
+func normalize(text string) string {
+	return strings.ToLower(strings.Join(rhymes.Tokenize(text), " "))
+}
+
+// sameShape checks whether a poem has the stanzas and lines of a form
+func sameShape(stanzas [][]*Line, formLines [][]*form.Line) bool {
+	if len(stanzas) != len(formLines) {
+		return false
+	}
+
+	for i := range stanzas {
+		if len(stanzas[i]) != len(formLines[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// fits checks whether a line of text fits the meter and syllable count of a
+// line of a form
+func fits(text string, line *form.Line, rhymer *rhymes.Rhymer) bool {
+	if line.Foot != nil {
+		wordStresses := rhymer.LineStresses(text)
+		if len(wordStresses) == 0 {
+			return false
+		}
+
+		if line.Feet > 0 {
+			return meter.Fit(wordStresses, *line.Foot, line.Feet, line.Variations).Deviation <= line.Tolerance
+		}
+		if meter.Scan(wordStresses, *line.Foot).Deviation > line.Tolerance {
+			return false
+		}
+	}
+
+	for _, count := range rhymer.LineSyllables(text) {
+		for _, length := range line.Lengths() {
+			if count == length {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// rhymeAgreement is the share of the pairs of lines rhyming in the form or in
+// the poem that rhyme in both. When both lines are refrains in the form, they
+// also need to repeat each other if they're the same refrain, and not if they
+// aren't.
+func rhymeAgreement(lines []*Line, expected []*form.Line) float64 {
+	pairs := 0
+	agreeing := 0
+	for i := range lines {
+		for j := i + 1; j < len(lines); j++ {
+			if expected[i].Rhyme == "" || expected[j].Rhyme == "" {
+				continue
+			}
+
+			rhyming := lines[i].Rhyme != "-" && rhymeGroup(lines[i].Rhyme) == rhymeGroup(lines[j].Rhyme)
+			expectedRhyming := expected[i].Rhyme == expected[j].Rhyme
+			if !rhyming && !expectedRhyming {
+				continue
+			}
+
+			pairs++
+			agrees := rhyming && expectedRhyming
+			if expected[i].Refrain != "" && expected[j].Refrain != "" {
+				repeated := normalize(lines[i].Text) == normalize(lines[j].Text)
+				agrees = agrees && repeated == (expected[i].Refrain == expected[j].Refrain)
+			}
+
+			if agrees {
+				agreeing++
+			}
+		}
+	}
+
+	if pairs == 0 {
+		return 1
+	}
+
+	return float64(agreeing) / float64(pairs)
+}
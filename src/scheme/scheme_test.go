@@ -0,0 +1,152 @@
+package scheme
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/verkestk/goetry/src/corpus"
+	"github.com/verkestk/goetry/src/form"
+	"github.com/verkestk/goetry/src/rhymes"
+)
+
+const testPoem = `He walks along
+Here is the sound
+My time so long
+Is all around
+
+A little man
+The street is hard
+My little span
+A bodyguard
+
+The little shot
+Is what I got
+`
+
+func loadTestRhymer(t *testing.T, stanzas [][]string) *rhymes.Rhymer {
+	lines := []string{}
+	for _, stanza := range stanzas {
+		lines = append(lines, stanza...)
+	}
+
+	rhmr, err := rhymes.Load("../rhymes/test_dictionary.txt", &corpus.Corpus{Lines: lines})
+	if err != nil {
+		t.Fatalf("Error loading pronunciation dictionary: %v", err)
+	}
+
+	return rhmr
+}
+
+func Test_Parse(t *testing.T) {
+	stanzas := Parse("\r\n  one\r\ntwo  \n\n\n\nthree\n")
+	expected := [][]string{[]string{"one", "two"}, []string{"three"}}
+	if !reflect.DeepEqual(expected, stanzas) {
+		t.Errorf("expected %v, got %v", expected, stanzas)
+	}
+}
+
+func Test_Detect(t *testing.T) {
+	stanzas := Parse(testPoem)
+	rhmr := loadTestRhymer(t, stanzas)
+
+	analysis := Detect(stanzas, rhmr, 1)
+	expected := "ABAB CDCD EE"
+	if analysis.Scheme() != expected {
+		t.Errorf("expected scheme %s, got %s", expected, analysis.Scheme())
+	}
+
+	line := analysis.Stanzas[1][3]
+	if line.EndWord != "bodyguard" || strings.Join(line.Pronunciation, " ") != "B AA1 D IY0 G AA2 R D" {
+		t.Errorf("unexpected end word %s (%v)", line.EndWord, line.Pronunciation)
+	}
+	if line.Scansion == nil || line.Scansion.Foot.Name != "iamb" {
+		t.Errorf("expected \"%s\" to be iambic, got %v", line.Text, line.Scansion)
+	}
+}
+
+func Test_Detect_pronunciations(t *testing.T) {
+	// "in" and "when" only rhyme with their second and third pronunciations
+	stanzas := [][]string{[]string{"I got in", "I got when", "a man"}}
+	rhmr := loadTestRhymer(t, stanzas)
+
+	analysis := Detect(stanzas, rhmr, 1)
+	if analysis.Scheme() != "AA-" {
+		t.Errorf("expected scheme AA-, got %s", analysis.Scheme())
+	}
+	if strings.Join(analysis.Stanzas[0][0].Pronunciation, " ") != "IH1 N" {
+		t.Errorf("expected pronunciation IH1 N for \"in\", got %v", analysis.Stanzas[0][0].Pronunciation)
+	}
+	if strings.Join(analysis.Stanzas[0][1].Pronunciation, " ") != "W IH1 N" {
+		t.Errorf("expected pronunciation W IH1 N for \"when\", got %v", analysis.Stanzas[0][1].Pronunciation)
+	}
+}
+
+func Test_Detect_unknown(t *testing.T) {
+	// "qwerty" isn't in the dictionary, and "..." has no words at all
+	stanzas := [][]string{[]string{"He walks along", "zorblax qwerty", "My time so long", "..."}}
+	rhmr := loadTestRhymer(t, stanzas)
+
+	analysis := Detect(stanzas, rhmr, 1)
+	if analysis.Scheme() != "A-A-" {
+		t.Errorf("expected scheme A-A-, got %s", analysis.Scheme())
+	}
+	if analysis.Stanzas[0][1].Pronunciation != nil || analysis.Stanzas[0][3].EndWord != "" {
+		t.Errorf("unexpected end words %+v, %+v", analysis.Stanzas[0][1], analysis.Stanzas[0][3])
+	}
+}
+
+func Test_Detect_refrains(t *testing.T) {
+	stanzas := [][]string{
+		[]string{"Here is the sound!", "Is all around", "here is the sound"},
+		[]string{"A little man", "My little span", "Here is the sound"},
+	}
+	rhmr := loadTestRhymer(t, stanzas)
+
+	analysis := Detect(stanzas, rhmr, 1)
+	if analysis.Scheme() != "A1AA1 BBA1" {
+		t.Errorf("expected scheme A1AA1 BBA1, got %s", analysis.Scheme())
+	}
+}
+
+func Test_Detect_manyGroups(t *testing.T) {
+	// 30 couplets, each ending in a different made up word, and a line that
+	// rhymes with none of them
+	stanzas := [][]string{}
+	for i := 0; i < 30; i++ {
+		word := "zo" + string(rune('a'+i/26)) + string(rune('a'+i%26))
+		stanzas = append(stanzas, []string{"I said " + word, "you said " + word})
+	}
+	stanzas = append(stanzas, []string{"I said zzz"})
+	rhmr := loadTestRhymer(t, stanzas)
+
+	analysis := Detect(stanzas, rhmr, 1)
+	expected := map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 29: "AD", 30: "-"}
+	for i, rhyme := range expected {
+		for _, line := range analysis.Stanzas[i] {
+			if line.Rhyme != rhyme {
+				t.Errorf("expected rhyme %s for \"%s\", got %s", rhyme, line.Text, line.Rhyme)
+			}
+		}
+	}
+}
+
+func Test_Analysis_GuessForm(t *testing.T) {
+	stanzas := Parse(testPoem)
+	rhmr := loadTestRhymer(t, stanzas)
+	analysis := Detect(stanzas, rhmr, 1)
+
+	haiku := &form.Form{Name: "haiku", Stanzas: []int{3}, Syllables: []int{5, 7, 5}}
+	paired := &form.Form{Name: "paired", Rhyme: "AABB CCDD EE", Syllables: []int{4}}
+	crossed := &form.Form{Name: "crossed", Rhyme: "ABAB CDCD EE", Syllables: []int{4}}
+
+	guessed, score := analysis.GuessForm([]*form.Form{haiku, paired, crossed}, rhmr)
+	if guessed != crossed || score != 1 {
+		t.Errorf("expected crossed form with score 1, got %v with score %f", guessed, score)
+	}
+
+	guessed, score = analysis.GuessForm([]*form.Form{haiku, paired}, rhmr)
+	if guessed != nil {
+		t.Errorf("expected no form, got %v with score %f", guessed, score)
+	}
+}
@@ -0,0 +1,216 @@
+package sound
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/verkestk/goetry/src/rhymes"
+)
+
+// DeviceKind is a kind of repeated sound
+type DeviceKind int
+
+const (
+	// Alliteration is words whose stressed syllables start with the same
+	// consonant, like "soft sound"
+	Alliteration DeviceKind = iota
+
+	// Assonance is words whose stressed syllables have the same vowel, like
+	// "long lost"
+	Assonance
+
+	// Consonance is words with the same consonant closing a syllable, like
+	// "hard third"
+	Consonance
+)
+
+func (k DeviceKind) String() string {
+	switch k {
+	case Alliteration:
+		return "alliteration"
+	case Assonance:
+		return "assonance"
+	case Consonance:
+		return "consonance"
+	}
+	return "unknown"
+}
+
+// Position is where a sound is in a line
+type Position struct {
+	// the index of the word, in the words of the line
+	Word int
+
+	// the index of the syllable, in the syllables of the word
+	Syllable int
+}
+
+// Device is a sound repeated across the words of a line
+type Device struct {
+	Kind DeviceKind
+
+	// the repeated phoneme, without stress
+	Sound string
+
+	// where the sound is, at most once for each word, in order
+	Positions []Position
+
+	// how strongly the sound is repeated - each word with the sound after the
+	// first adds 1 divided by the number of words since the last one, so two
+	// words next to each other are 1, and three words next to each other are 2
+	Strength float64
+}
+
+// Analysis is the sound devices of a line
+type Analysis struct {
+	Line string
+
+	// the words of the line, as split by rhymes.Tokenize
+	Words []string
+
+	// the devices, strongest first
+	Devices []*Device
+
+	// the total strength of the devices - how musical the line is
+	Score float64
+}
+
+// syllable is a vowel, the consonants before it and the consonants after it, and
+// whether the vowel is stressed
+type syllable struct {
+	onset    []string
+	vowel    string
+	coda     []string
+	stressed bool
+}
+
+// Analyze finds the sound devices of a line, using the first pronunciation of
+// each word. Words without a pronunciation are skipped.
+func Analyze(line string, rhymer *rhymes.Rhymer) *Analysis {
+	analysis := &Analysis{Line: line, Words: rhymes.Tokenize(line)}
+
+	// for each kind of device, the positions of each sound
+	found := map[DeviceKind]map[string][]Position{
+		Alliteration: {},
+		Assonance:    {},
+		Consonance:   {},
+	}
+	add := func(kind DeviceKind, sound string, position Position) {
+		positions := found[kind][sound]
+		if len(positions) > 0 && positions[len(positions)-1].Word == position.Word {
+			return
+		}
+		found[kind][sound] = append(positions, position)
+	}
+
+	for i, word := range analysis.Words {
+		pronunciations := rhymer.Pronunciations(word)
+		if len(pronunciations) == 0 {
+			continue
+		}
+
+		for j, s := range syllables(pronunciations[0]) {
+			position := Position{Word: i, Syllable: j}
+			if s.stressed {
+				if len(s.onset) > 0 {
+					add(Alliteration, s.onset[0], position)
+				}
+				add(Assonance, s.vowel, position)
+			}
+			for _, consonant := range s.coda {
+				add(Consonance, consonant, position)
+			}
+		}
+	}
+
+	for _, kind := range []DeviceKind{Alliteration, Assonance, Consonance} {
+		for sound, positions := range found[kind] {
+			if len(positions) < 2 {
+				continue
+			}
+
+			device := &Device{Kind: kind, Sound: sound, Positions: positions}
+			for i := 1; i < len(positions); i++ {
+				device.Strength += 1 / float64(positions[i].Word-positions[i-1].Word)
+			}
+
+			analysis.Devices = append(analysis.Devices, device)
+			analysis.Score += device.Strength
+		}
+	}
+
+	sort.Slice(analysis.Devices, func(i, j int) bool {
+		a, b := analysis.Devices[i], analysis.Devices[j]
+		if a.Strength != b.Strength {
+			return a.Strength > b.Strength
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Sound < b.Sound
+	})
+
+	return analysis
+}
+
+// Musicality scores the sound of a poem or any other lines - the average Score
+// of their Analysis
+func Musicality(lines []string, rhymer *rhymes.Rhymer) float64 {
+	if len(lines) == 0 {
+		return 0
+	}
+
+	total := 0.0
+	for _, line := range lines {
+		total += Analyze(line, rhymer).Score
+	}
+
+	return total / float64(len(lines))
+}
+
+// Describe describes the device, e.g. `alliteration of S (2.00): soft, sound, so`,
+// given the words of its line
+func (d *Device) Describe(words []string) string {
+	found := []string{}
+	for _, position := range d.Positions {
+		found = append(found, strings.ToLower(words[position.Word]))
+	}
+
+	return fmt.Sprintf("%s of %s (%.2f): %s", d.Kind, d.Sound, d.Strength, strings.Join(found, ", "))
+}
+
+// syllables splits a pronunciation into syllables. A consonant between two
+// vowels starts the second syllable, and any other consonants before it close
+// the first.
+func syllables(pronunciation []string) []*syllable {
+	found := []*syllable{}
+	consonants := []string{}
+	for _, phoneme := range pronunciation {
+		if !unicode.IsDigit(rune(phoneme[len(phoneme)-1])) {
+			consonants = append(consonants, phoneme)
+			continue
+		}
+
+		s := &syllable{
+			vowel:    phoneme[:len(phoneme)-1],
+			stressed: !strings.HasSuffix(phoneme, "0"),
+		}
+		if len(found) == 0 {
+			s.onset = consonants
+		} else if len(consonants) > 0 {
+			found[len(found)-1].coda = consonants[:len(consonants)-1]
+			s.onset = consonants[len(consonants)-1:]
+		}
+
+		found = append(found, s)
+		consonants = []string{}
+	}
+
+	if len(found) > 0 {
+		found[len(found)-1].coda = consonants
+	}
+
+	return found
+}
@@ -0,0 +1,105 @@
+package sound
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/verkestk/goetry/src/corpus"
+	"github.com/verkestk/goetry/src/rhymes"
+)
+
+func loadTestRhymer(t *testing.T, lines []string) *rhymes.Rhymer {
+	rhmr, err := rhymes.Load("../rhymes/test_dictionary.txt", &corpus.Corpus{Lines: lines})
+	if err != nil {
+		t.Fatalf("Error loading pronunciation dictionary: %v", err)
+	}
+
+	return rhmr
+}
+
+func findDevice(analysis *Analysis, kind DeviceKind, sound string) *Device {
+	for _, device := range analysis.Devices {
+		if device.Kind == kind && device.Sound == sound {
+			return device
+		}
+	}
+
+	return nil
+}
+
+func Test_Analyze(t *testing.T) {
+	lines := []string{"Soft sound, so long", "The long lost hard third", "Speak, spinning span", "What"}
+	rhmr := loadTestRhymer(t, lines)
+
+	expected := []struct {
+		line      string
+		kind      DeviceKind
+		sound     string
+		positions []Position
+		strength  float64
+	}{
+		{lines[0], Alliteration, "S", []Position{Position{0, 0}, Position{1, 0}, Position{2, 0}}, 2},
+		{lines[1], Alliteration, "L", []Position{Position{1, 0}, Position{2, 0}}, 1},
+		{lines[1], Assonance, "AO", []Position{Position{1, 0}, Position{2, 0}}, 1},
+		{lines[1], Consonance, "D", []Position{Position{3, 0}, Position{4, 0}}, 1},
+		{lines[1], Consonance, "T", []Position{Position{2, 0}}, 0},
+		{lines[2], Alliteration, "S", []Position{Position{0, 0}, Position{1, 0}, Position{2, 0}}, 2},
+		{lines[2], Consonance, "NG", nil, 0},
+	}
+
+	for _, e := range expected {
+		analysis := Analyze(e.line, rhmr)
+		device := findDevice(analysis, e.kind, e.sound)
+		if len(e.positions) < 2 {
+			if device != nil {
+				t.Errorf("expected no %s of %s in \"%s\", got %s", e.kind, e.sound, e.line, device.Describe(analysis.Words))
+			}
+			continue
+		}
+
+		if device == nil {
+			t.Errorf("expected %s of %s in \"%s\", got none", e.kind, e.sound, e.line)
+			continue
+		}
+		if !reflect.DeepEqual(device.Positions, e.positions) {
+			t.Errorf("expected %s of %s at %v in \"%s\", got %v", e.kind, e.sound, e.positions, e.line, device.Positions)
+		}
+		if device.Strength != e.strength {
+			t.Errorf("expected %s of %s in \"%s\" to have strength %f, got %f", e.kind, e.sound, e.line, e.strength, device.Strength)
+		}
+	}
+
+	// the devices are ordered by strength, and add up to the score
+	analysis := Analyze(lines[1], rhmr)
+	total := 0.0
+	for i, device := range analysis.Devices {
+		total += device.Strength
+		if i > 0 && analysis.Devices[i-1].Strength < device.Strength {
+			t.Errorf("expected devices in descending order of strength, got %v", analysis.Devices)
+		}
+	}
+	if total != analysis.Score {
+		t.Errorf("expected score %f, got %f", total, analysis.Score)
+	}
+
+	if Analyze(lines[3], rhmr).Score != 0 {
+		t.Errorf("expected a score of 0 for a single word")
+	}
+	if Musicality(lines, rhmr) <= 0 {
+		t.Errorf("expected lines to have some musicality")
+	}
+}
+
+func Test_syllables(t *testing.T) {
+	found := syllables([]string{"S", "P", "IH1", "N", "IH0", "NG"})
+	if len(found) != 2 {
+		t.Fatalf("expected 2 syllables, got %d", len(found))
+	}
+
+	if !reflect.DeepEqual(found[0], &syllable{onset: []string{"S", "P"}, vowel: "IH", coda: []string{}, stressed: true}) {
+		t.Errorf("unexpected first syllable %v", found[0])
+	}
+	if !reflect.DeepEqual(found[1], &syllable{onset: []string{"N"}, vowel: "IH", coda: []string{"NG"}, stressed: false}) {
+		t.Errorf("unexpected second syllable %v", found[1])
+	}
+}
@@ -65,3 +65,115 @@ func generateSentences(chain *markovokram.Chain, length int) []string {
 	}
 	return sentences
 }
+
+// SyllableCounter reports every syllable count a generated token can have. An
+// empty result means the token's syllables are unknown.
+type SyllableCounter func(token string) []int
+
+// LineFilter decides whether a generated line of words is acceptable. A nil
+// LineFilter accepts every line.
+type LineFilter func(tokens []string) bool
+
+// GenerateSyllables generates a line of words from Chain with exactly
+// _syllables_ syllables that passes filter, continuing on from the words in
+// prefix (which can be empty to start fresh). Rather than looping forever, it
+// backtracks when a line overshoots, runs out of chain, or is filtered out, and
+// gives up after drawing _attempts_ words. Returns nil if no line could be
+// generated.
+func GenerateSyllables(chain *markovokram.Chain, prefix []string, syllables int, count SyllableCounter, filter LineFilter, attempts int) []string {
+	budget := attempts
+	return generateSyllables(chain.GenerateForwardFromPrefix, prefix, []string{}, map[int]bool{0: true}, syllables, count, filter, &budget)
+}
+
+// GenerateSyllablesBackward generates a line of words from Chain with exactly
+// _syllables_ syllables that passes filter and ends with the words in suffix,
+// walking the chain backward from the end of the line. The suffix counts
+// towards the syllables, and should be a sequence of words that appears in the
+// chain. Like GenerateSyllables, it backtracks and gives up after drawing
+// _attempts_ words. Returns nil if no line could be generated.
+func GenerateSyllablesBackward(chain *markovokram.Chain, suffix []string, syllables int, count SyllableCounter, filter LineFilter, attempts int) []string {
+	// walking backward, the chain expects the words in reverse order
+	reversed := reverse(suffix)
+
+	totals := map[int]bool{0: true}
+	for _, token := range suffix {
+		totals = addSyllables(totals, count(token), syllables)
+	}
+	if len(totals) == 0 {
+		return nil
+	}
+
+	// the filter sees the line in order, not the reversed words being generated
+	var reversedFilter LineFilter
+	if filter != nil {
+		reversedFilter = func(tokens []string) bool {
+			return filter(append(reverse(tokens), suffix...))
+		}
+	}
+
+	budget := attempts
+	tokens := generateSyllables(chain.GenerateBackwardFromPrefix, reversed, []string{}, totals, syllables, count, reversedFilter, &budget)
+	if tokens == nil {
+		return nil
+	}
+
+	return append(reverse(tokens), suffix...)
+}
+
+// the number of different words tried at each position before backtracking
+const syllableBranching = 5
+
+// depth first search for a line, drawing candidates for each position at random
+// from the chain
+func generateSyllables(walk func([]string) *markovokram.Generation, prefix, tokens []string, totals map[int]bool, syllables int, count SyllableCounter, filter LineFilter, budget *int) []string {
+	if totals[syllables] && (filter == nil || filter(tokens)) {
+		return tokens
+	}
+
+	context := append(append([]string{}, prefix...), tokens...)
+	for tries := 0; tries < syllableBranching && *budget > 0; tries++ {
+		*budget--
+
+		// the generation shifts the prefix it's given, so give it a copy
+		next := walk(append([]string{}, context...)).Next()
+		if next == "" {
+			continue
+		}
+
+		nextTotals := addSyllables(totals, count(next), syllables)
+		if len(nextTotals) == 0 {
+			continue
+		}
+
+		line := generateSyllables(walk, prefix, append(append([]string{}, tokens...), next), nextTotals, syllables, count, filter, budget)
+		if line != nil {
+			return line
+		}
+	}
+
+	return nil
+}
+
+// adds every one of a token's syllable counts to every running total, dropping
+// any that go over the maximum
+func addSyllables(totals map[int]bool, counts []int, max int) map[int]bool {
+	nextTotals := map[int]bool{}
+	for total := range totals {
+		for _, c := range counts {
+			if total+c <= max {
+				nextTotals[total+c] = true
+			}
+		}
+	}
+
+	return nextTotals
+}
+
+func reverse(tokens []string) []string {
+	reversed := make([]string, len(tokens))
+	for i, token := range tokens {
+		reversed[len(tokens)-i-1] = token
+	}
+
+	return reversed
+}